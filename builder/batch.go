@@ -0,0 +1,123 @@
+package builder
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Batch collects pgsql builders to send them to the server in a single
+// round trip.
+type Batch struct {
+	builders []SqlBuilder[pgx.Row, pgx.Rows]
+}
+
+// BatchResult is the result of one builder of a Batch. Queries returning
+// rows fill Columns and Rows, writes only RowsAffected.
+type BatchResult struct {
+	Query        string
+	Args         []any
+	Columns      []string
+	Rows         [][]any
+	RowsAffected int64
+}
+
+// BatchError reports the builder of a Batch that failed, by its position.
+type BatchError struct {
+	Index int
+	Query string
+	Args  []any
+	Err   error
+}
+
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("builder: batch query %d %q failed: %v", e.Index, e.Query, e.Err)
+}
+
+func (e *BatchError) Unwrap() error {
+	return e.Err
+}
+
+func NewBatch(builders ...SqlBuilder[pgx.Row, pgx.Rows]) *Batch {
+	return &Batch{builders: builders}
+}
+
+// Add queues more builders, they are sent in the order they were added.
+func (b *Batch) Add(builders ...SqlBuilder[pgx.Row, pgx.Rows]) *Batch {
+	b.builders = append(b.builders, builders...)
+	return b
+}
+
+func (b *Batch) Len() int {
+	return len(b.builders)
+}
+
+// Send runs every builder with pgx SendBatch and reads their results in
+// order. When a query fails the results read so far are returned with a
+// *BatchError pointing at the failing builder, the server skipping the
+// queries following it. The Before hooks of each builder run as it is
+// queued and its After hooks as its result is read, the skipped queries
+// reporting the failure as well.
+func (b *Batch) Send(ctx context.Context, conn PgxBatchSender) ([]BatchResult, error) {
+	if isNilConnection(conn) {
+		return nil, ErrNilConnection
+	}
+	batch := &pgx.Batch{}
+	results := make([]BatchResult, 0, len(b.builders))
+	infos := make([]*QueryInfo, len(b.builders))
+	afters := make([]func(err error), len(b.builders))
+	for i, v := range b.builders {
+		res := BatchResult{Query: v.ToQueryString(), Args: v.GetArgsValue()}
+		afters[i] = func(err error) {}
+		if sb, ok := v.(*sqlBuilder[pgx.Row, pgx.Rows]); ok {
+			_, infos[i], afters[i] = sb.beforeHooks(ctx, res.Query, res.Args)
+		}
+		batch.Queue(res.Query, res.Args...)
+		results = append(results, res)
+	}
+	br := conn.SendBatch(ctx, batch)
+	for i := range results {
+		if err := readBatchResult(br, &results[i]); err != nil {
+			br.Close()
+			afters[i](err)
+			for j := i + 1; j < len(afters); j++ {
+				afters[j](fmt.Errorf("builder: batch query %d skipped after query %d failed: %w", j, i, err))
+			}
+			return results[:i], &BatchError{Index: i, Query: results[i].Query, Args: results[i].Args, Err: err}
+		}
+		if info := infos[i]; info != nil {
+			if len(results[i].Columns) > 0 {
+				info.RowsReturned = int64(len(results[i].Rows))
+			} else {
+				info.RowsAffected = results[i].RowsAffected
+			}
+		}
+		afters[i](nil)
+	}
+	return results, br.Close()
+}
+
+func readBatchResult(br pgx.BatchResults, res *BatchResult) error {
+	rows, err := br.Query()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for _, v := range rows.FieldDescriptions() {
+		res.Columns = append(res.Columns, v.Name)
+	}
+	for rows.Next() {
+		values, err := rows.Values()
+		if err != nil {
+			return err
+		}
+		res.Rows = append(res.Rows, values)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	res.RowsAffected = rows.CommandTag().RowsAffected()
+	return nil
+}
@@ -0,0 +1,131 @@
+package builder_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	builder "github.com/zhuan69/go-simple-sql-builder/builder"
+	mocks "github.com/zhuan69/go-simple-sql-builder/tests/mocks/postgresql"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func batchOf(n int) any {
+	return mock.MatchedBy(func(b *pgx.Batch) bool {
+		return b.Len() == n
+	})
+}
+
+func TestItCanSendBatchInOrder(t *testing.T) {
+	ctx := context.Background()
+	results := &mocks.MockBatchResults{Results: []mocks.MockBatchResult{
+		{Rows: mocks.NewMockRows([]string{"id", "name"}, []any{int64(1), "zhuan"})},
+		{Rows: mocks.NewMockRows(nil).WithCommandTag("UPDATE 2")},
+	}}
+	pool := new(mocks.MockPgxPool)
+	pool.On("SendBatch", ctx, batchOf(2)).Return(results)
+
+	selectUsers := builder.NewPgsqlBuilder(ctx, pool, "users").Select([]string{"id", "name"}).Where("id", 1)
+	updateUsers := builder.NewPgsqlBuilder(ctx, pool, "users").Update(map[string]any{"active": false}).Where("name", "akbar")
+	res, err := builder.NewBatch(selectUsers).Add(updateUsers).Send(ctx, pool)
+	require.NoError(t, err)
+	require.Len(t, res, 2)
+	assert.Equal(t, "SELECT id,name FROM users WHERE id=$1", res[0].Query)
+	assert.Equal(t, []any{1}, res[0].Args)
+	assert.Equal(t, []string{"id", "name"}, res[0].Columns)
+	assert.Equal(t, [][]any{{int64(1), "zhuan"}}, res[0].Rows)
+	assert.Equal(t, "UPDATE users SET active=$1 WHERE name=$2", res[1].Query)
+	assert.Equal(t, int64(2), res[1].RowsAffected)
+	assert.True(t, results.IsClosed())
+	pool.AssertExpectations(t)
+}
+
+func TestItCanAttributeBatchErrorToBuilder(t *testing.T) {
+	ctx := context.Background()
+	failure := errors.New("duplicate key value violates unique constraint")
+	results := &mocks.MockBatchResults{Results: []mocks.MockBatchResult{
+		{Rows: mocks.NewMockRows(nil).WithCommandTag("INSERT 0 1")},
+		{Err: failure},
+		{Err: failure},
+	}}
+	pool := new(mocks.MockPgxPool)
+	pool.On("SendBatch", ctx, batchOf(3)).Return(results)
+
+	batch := builder.NewBatch(
+		builder.NewPgsqlBuilder(ctx, pool, "users").Insert(map[string]any{"name": "zhuan"}),
+		builder.NewPgsqlBuilder(ctx, pool, "users").Insert(map[string]any{"name": "zhuan"}),
+		builder.NewPgsqlBuilder(ctx, pool, "users").Delete().Where("name", "akbar"),
+	)
+	res, err := batch.Send(ctx, pool)
+	var batchErr *builder.BatchError
+	require.ErrorAs(t, err, &batchErr)
+	assert.Equal(t, 1, batchErr.Index)
+	assert.Equal(t, "INSERT INTO users (name) VALUES ($1)", batchErr.Query)
+	assert.ErrorIs(t, err, failure)
+	require.Len(t, res, 1)
+	assert.Equal(t, int64(1), res[0].RowsAffected)
+	assert.True(t, results.IsClosed())
+}
+
+func TestItCanRejectBatchWithoutConnection(t *testing.T) {
+	_, err := builder.NewBatch().Send(context.Background(), nil)
+	assert.ErrorIs(t, err, builder.ErrNilConnection)
+}
+
+func TestItCanRunHooksForEveryBatchQuery(t *testing.T) {
+	ctx := context.Background()
+	failure := errors.New("duplicate key value violates unique constraint")
+	results := &mocks.MockBatchResults{Results: []mocks.MockBatchResult{
+		{Rows: mocks.NewMockRows([]string{"id"}, []any{int64(1)}, []any{int64(2)})},
+		{Err: failure},
+		{Err: failure},
+	}}
+	pool := new(mocks.MockPgxPool)
+	pool.On("SendBatch", ctx, batchOf(3)).Return(results)
+
+	var events []string
+	var errs []error
+	var infos []builder.QueryInfo
+	hook := builder.HookFuncs{
+		BeforeFunc: func(ctx context.Context, query string, args []any) context.Context {
+			events = append(events, "before "+query)
+			return ctx
+		},
+		AfterFunc: func(ctx context.Context, query string, args []any, d time.Duration, err error) {
+			events = append(events, "after "+query)
+			errs = append(errs, err)
+			info, ok := builder.QueryInfoFromContext(ctx)
+			require.True(t, ok)
+			infos = append(infos, *info)
+		},
+	}
+	_, err := builder.NewBatch(
+		builder.NewPgsqlBuilder(ctx, pool, "users").Select([]string{"id"}).WithHooks(hook),
+		builder.NewPgsqlBuilder(ctx, pool, "users").Insert(map[string]any{"name": "zhuan"}).WithHooks(hook),
+		builder.NewPgsqlBuilder(ctx, pool, "orders").Delete().Where("id", 1).WithHooks(hook),
+	).Send(ctx, pool)
+	require.ErrorIs(t, err, failure)
+
+	assert.Equal(t, []string{
+		"before SELECT id FROM users",
+		"before INSERT INTO users (name) VALUES ($1)",
+		"before DELETE FROM orders WHERE id=$1",
+		"after SELECT id FROM users",
+		"after INSERT INTO users (name) VALUES ($1)",
+		"after DELETE FROM orders WHERE id=$1",
+	}, events)
+	require.Len(t, errs, 3)
+	assert.NoError(t, errs[0])
+	assert.Same(t, failure, errs[1])
+	assert.ErrorIs(t, errs[2], failure)
+	assert.Equal(t, "SELECT", infos[0].Operation)
+	assert.Equal(t, int64(2), infos[0].RowsReturned)
+	assert.Equal(t, "INSERT", infos[1].Operation)
+	assert.Equal(t, "orders", infos[2].Table)
+	assert.Equal(t, []string{"id"}, infos[2].Columns)
+}
@@ -0,0 +1,59 @@
+package builder_test
+
+import (
+	"context"
+	"testing"
+
+	builder "github.com/zhuan69/go-simple-sql-builder/builder"
+)
+
+func BenchmarkRenderSelectPgsql(b *testing.B) {
+	sb := builder.NewPgsqlBuilder(context.Background(), nil, "users")
+	sb.Select([]string{"id", "name", "email", "created_at"}).Where("active", true).Where("role", "admin").
+		OrderBy("created_at", "desc").Limit(20).Offset(40)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = sb.ToQueryString()
+		_ = sb.GetArgsValue()
+	}
+}
+
+func BenchmarkRenderSelectMysql(b *testing.B) {
+	sb := builder.NewMysqlBuilder(context.Background(), nil, "users")
+	sb.Select([]string{"id", "name"}).Where("active", true).WhereLike("name", "zhu%").OrderBy("id", "asc").Limit(10)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = sb.ToQueryString()
+		_ = sb.GetArgsValue()
+	}
+}
+
+func BenchmarkRenderInsertPgsql(b *testing.B) {
+	sb := builder.NewPgsqlBuilder(context.Background(), nil, "users")
+	sb.Insert(map[string]any{"name": "zhuan", "email": "zhuan@mail.com", "active": true, "role": "admin"})
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = sb.ToQueryString()
+		_ = sb.GetArgsValue()
+	}
+}
+
+func BenchmarkRenderUpdateMysql(b *testing.B) {
+	sb := builder.NewMysqlBuilder(context.Background(), nil, "users")
+	sb.Update(map[string]any{"name": "zhuan", "email": "zhuan@mail.com"}).Where("id", 1)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = sb.ToQueryString()
+		_ = sb.GetArgsValue()
+	}
+}
+
+func BenchmarkRenderUpdateMssql(b *testing.B) {
+	sb := builder.NewMssqlBuilder(context.Background(), nil, "users")
+	sb.Update(map[string]any{"name": "zhuan", "email": "zhuan@mail.com"}).Where("id", 1)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = sb.ToQueryString()
+		_ = sb.GetArgsValue()
+	}
+}
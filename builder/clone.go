@@ -0,0 +1,23 @@
+package builder
+
+import (
+	"slices"
+
+	observable "github.com/zhuan69/go-simple-sql-builder/observable"
+)
+
+// Clone implements SqlBuilder
+func (sb *sqlBuilder[RT, RWT]) Clone() SqlBuilder[RT, RWT] {
+	return sb.withObserve(sb.SqlObserve)
+}
+
+// withObserve returns a copy of the builder rendering observes, with its own
+// observe and hook slices so it is safe to extend without touching the
+// original builder. The observes themselves are shared, they are never
+// modified once registered.
+func (sb *sqlBuilder[RT, RWT]) withObserve(observes []observable.SqlObserve) *sqlBuilder[RT, RWT] {
+	cp := *sb
+	cp.SqlObserve = append(make([]observable.SqlObserve, 0, len(observes)+2), observes...)
+	cp.hooks = slices.Clone(sb.hooks)
+	return &cp
+}
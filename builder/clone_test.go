@@ -0,0 +1,44 @@
+package builder_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	builder "github.com/zhuan69/go-simple-sql-builder/builder"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestItCanBranchClonedBuilders(t *testing.T) {
+	base := builder.NewPgsqlBuilder(context.Background(), nil, "users")
+	base.Select([]string{"id", "name"}).Where("active", true)
+
+	admins := base.Clone().Where("role", "admin").OrderBy("id", "asc")
+	guests := base.Clone().Where("role", "guest").Limit(5)
+
+	assert.Equal(t, "SELECT id,name FROM users WHERE active=$1", base.ToQueryString())
+	assert.Equal(t, []any{true}, base.GetArgsValue())
+	assert.Equal(t, "SELECT id,name FROM users WHERE active=$1 AND role=$2 ORDER BY id asc", admins.ToQueryString())
+	assert.Equal(t, []any{true, "admin"}, admins.GetArgsValue())
+	assert.Equal(t, "SELECT id,name FROM users WHERE active=$1 AND role=$2 LIMIT 5", guests.ToQueryString())
+	assert.Equal(t, []any{true, "guest"}, guests.GetArgsValue())
+}
+
+func TestItCanCloneBuilderAcrossGoroutines(t *testing.T) {
+	base := builder.NewMysqlBuilder(context.Background(), nil, "users")
+	base.Select([]string{"id"}).Where("active", true)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			sb := base.Clone().Where("id", i)
+			assert.Equal(t, []any{true, i}, sb.GetArgsValue())
+			assert.Equal(t, "SELECT id FROM users WHERE active=? AND id=?", sb.ToQueryString())
+			assert.Equal(t, "SELECT id FROM users WHERE active=?", base.ToQueryString())
+		}(i)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,200 @@
+package builder
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	constants "github.com/zhuan69/go-simple-sql-builder/constants"
+	observable "github.com/zhuan69/go-simple-sql-builder/observable"
+)
+
+// CopyFromSource is a row source read by CopyFrom, it has the method set of
+// pgx.CopyFromSource so pgx.CopyFromRows and pgx.CopyFromSlice can be used.
+type CopyFromSource interface {
+	Next() bool
+	Values() ([]any, error)
+	Err() error
+}
+
+type copyRows struct {
+	n      int
+	row    int
+	values func(row int) ([]any, error)
+}
+
+func (c *copyRows) Next() bool {
+	c.row++
+	return c.row <= c.n
+}
+
+func (c *copyRows) Values() ([]any, error) {
+	return c.values(c.row - 1)
+}
+
+func (c *copyRows) Err() error {
+	return nil
+}
+
+// CopyFrom implements SqlBuilder
+func (sb *sqlBuilder[RT, RWT]) CopyFrom(columns []string, rows any) (int64, error) {
+	src, columns, err := copySource(columns, rows)
+	if err != nil {
+		return 0, err
+	}
+	if sb.execCopyCommand != nil {
+		query := fmt.Sprintf("COPY %s (%s) FROM STDIN", sb.tableName, strings.Join(columns, ","))
+		ctx, info, after := sb.beforeHooks(sb.ctx, query, nil)
+		n, err := sb.execCopyCommand(ctx, sb.tableName, columns, src)
+		info.RowsAffected = n
+		after(err)
+		return n, err
+	}
+	return sb.copyInsert(columns, src)
+}
+
+// copySource reads rows given as a CopyFromSource, [][]any, []map[string]any
+// or a slice of structs. Maps and structs default columns to the sorted keys
+// of the first map or the writable fields of the struct.
+func copySource(columns []string, rows any) (CopyFromSource, []string, error) {
+	switch r := rows.(type) {
+	case CopyFromSource:
+		if len(columns) == 0 {
+			return nil, nil, fmt.Errorf("builder: CopyFrom needs the columns of %T", rows)
+		}
+		return r, columns, nil
+	case [][]any:
+		if len(columns) == 0 {
+			return nil, nil, fmt.Errorf("builder: CopyFrom needs the columns of %T", rows)
+		}
+		return &copyRows{n: len(r), values: func(row int) ([]any, error) {
+			return r[row], nil
+		}}, columns, nil
+	case []map[string]any:
+		if len(columns) == 0 && len(r) > 0 {
+			for k := range r[0] {
+				columns = append(columns, k)
+			}
+			sort.Strings(columns)
+		}
+		return &copyRows{n: len(r), values: func(row int) ([]any, error) {
+			values := make([]any, len(columns))
+			for i, v := range columns {
+				values[i] = r[row][v]
+			}
+			return values, nil
+		}}, columns, nil
+	}
+	rv := reflect.ValueOf(rows)
+	if rv.Kind() != reflect.Slice {
+		return nil, nil, fmt.Errorf("builder: CopyFrom does not support %T", rows)
+	}
+	t := rv.Type().Elem()
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if !isNestedStruct(t) {
+		return nil, nil, fmt.Errorf("builder: CopyFrom does not support %T", rows)
+	}
+	sm := getStructMap(t)
+	if len(columns) == 0 {
+		for _, f := range sm.fields {
+			if !f.options["readonly"] {
+				columns = append(columns, f.column)
+			}
+		}
+	}
+	fields := make([]structField, len(columns))
+	for i, v := range columns {
+		field, ok := sm.byColumn[v]
+		if !ok {
+			return nil, nil, fmt.Errorf("builder: missing field for column %q in %s", v, t)
+		}
+		fields[i] = field
+	}
+	return &copyRows{n: rv.Len(), values: func(row int) ([]any, error) {
+		item := rv.Index(row)
+		for item.Kind() == reflect.Pointer {
+			if item.IsNil() {
+				return nil, fmt.Errorf("builder: CopyFrom row %d is nil", row)
+			}
+			item = item.Elem()
+		}
+		values := make([]any, len(fields))
+		for i, f := range fields {
+			if fv, ok := fieldValue(item, f.index); ok {
+				values[i] = fv.Interface()
+			}
+		}
+		return values, nil
+	}}, columns, nil
+}
+
+// copyChunkRows keeps every chunk under the bind parameter limit of the
+// dialect, MSSQL allowing 2100 parameters and 1000 rows per VALUES.
+func copyChunkRows(typeSql observable.SqlType, columns int) int {
+	limit := 65535
+	if typeSql == observable.MSSQL {
+		limit = 2000
+	}
+	rows := limit / columns
+	if rows > 1000 {
+		rows = 1000
+	}
+	if rows < 1 {
+		rows = 1
+	}
+	return rows
+}
+
+// copyInsert loads the rows with chunked multi-row INSERT, for connections
+// without the COPY protocol. Chunks are not atomic, run it inside WithTx to
+// load all or nothing.
+func (sb *sqlBuilder[RT, RWT]) copyInsert(columns []string, src CopyFromSource) (int64, error) {
+	if len(columns) == 0 {
+		return 0, fmt.Errorf("builder: CopyFrom needs at least one column")
+	}
+	chunk := copyChunkRows(sb.typeSql, len(columns))
+	values := make([]any, 0, chunk*len(columns))
+	var total int64
+	flush := func() error {
+		if len(values) == 0 {
+			return nil
+		}
+		n, err := sb.insertRows(columns, values)
+		total += n
+		values = values[:0]
+		return err
+	}
+	for src.Next() {
+		row, err := src.Values()
+		if err != nil {
+			return total, err
+		}
+		if len(row) != len(columns) {
+			return total, fmt.Errorf("builder: CopyFrom expected %d values but got %d", len(columns), len(row))
+		}
+		values = append(values, row...)
+		if len(values) == cap(values) {
+			if err := flush(); err != nil {
+				return total, err
+			}
+		}
+	}
+	if err := src.Err(); err != nil {
+		return total, err
+	}
+	return total, flush()
+}
+
+func (sb *sqlBuilder[RT, RWT]) insertRows(columns []string, values []any) (int64, error) {
+	ins := sb.withObserve(nil)
+	ins.paramStyle = ""
+	so := observable.NewSqlObserve(constants.INSERT_KEY, sb.typeSql, sb.tableName, true, 1, 0)
+	so.SetColumn(columns...)
+	so.SetValue(values...)
+	so.SetRows(len(values) / len(columns))
+	ins.registerObserve(so)
+	return ins.Exec()
+}
@@ -0,0 +1,88 @@
+package builder_test
+
+import (
+	"context"
+	"testing"
+
+	builder "github.com/zhuan69/go-simple-sql-builder/builder"
+	observable "github.com/zhuan69/go-simple-sql-builder/observable"
+	fakedb "github.com/zhuan69/go-simple-sql-builder/tests/mocks/fakedb"
+	mocks "github.com/zhuan69/go-simple-sql-builder/tests/mocks/postgresql"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestItCanCopyFromStructsPgsql(t *testing.T) {
+	ctx := context.Background()
+	pool := new(mocks.MockPgxPool)
+	pool.On("CopyFrom", ctx, pgx.Identifier{"public", "users"}, []string{"id", "name"},
+		[][]any{{int64(1), "zhuan"}, {int64(2), "akbar"}}).Return(int64(2), nil)
+
+	sb := builder.NewPgsqlBuilder(ctx, pool, "public.users")
+	n, err := sb.CopyFrom(nil, []*pageUser{{ID: 1, Name: "zhuan"}, {ID: 2, Name: "akbar"}})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), n)
+	pool.AssertExpectations(t)
+}
+
+func TestItCanCopyFromRowSourcePgsql(t *testing.T) {
+	ctx := context.Background()
+	pool := new(mocks.MockPgxPool)
+	pool.On("CopyFrom", ctx, pgx.Identifier{"users"}, []string{"name"}, mock.Anything).Return(int64(1), nil)
+
+	sb := builder.NewPgsqlBuilder(ctx, pool, "users")
+	n, err := sb.CopyFrom([]string{"name"}, pgx.CopyFromRows([][]any{{"zhuan"}}))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+}
+
+func TestItCanCopyFromMapsWithInsertMysql(t *testing.T) {
+	fake := fakedb.New(t, observable.MYSQL)
+	fake.ExpectQuery("INSERT INTO users (active,name) VALUES (?,?),(?,?)").
+		WithArgs(true, "zhuan", false, "akbar").WillReturnResult(2)
+
+	sb := builder.NewMysqlBuilder(context.Background(), fake.DB(), "users")
+	n, err := sb.CopyFrom(nil, []map[string]any{
+		{"name": "zhuan", "active": true},
+		{"name": "akbar", "active": false},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), n)
+}
+
+func TestItCanCopyFromInChunksMysql(t *testing.T) {
+	rows := make([][]any, 1500)
+	for i := range rows {
+		rows[i] = []any{i}
+	}
+	fake := fakedb.New(t, observable.MYSQL)
+	fake.ExpectQueryRegexp(`^INSERT INTO users \(id\) VALUES (\(\?\),){999}\(\?\)$`).WillReturnResult(1000)
+	fake.ExpectQueryRegexp(`^INSERT INTO users \(id\) VALUES (\(\?\),){499}\(\?\)$`).WillReturnResult(500)
+
+	sb := builder.NewMysqlBuilder(context.Background(), fake.DB(), "users")
+	n, err := sb.CopyFrom([]string{"id"}, rows)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1500), n)
+}
+
+func TestItCanCopyFromWithInsertAllOracle(t *testing.T) {
+	fake := fakedb.New(t, observable.ORACLE)
+	fake.ExpectQuery(`INSERT ALL INTO "users" ("id","name") VALUES (?,?) INTO "users" ("id","name") VALUES (?,?) SELECT 1 FROM dual`).
+		WithArgs(1, "zhuan", 2, "akbar").WillReturnResult(2)
+
+	sb := builder.NewOracleBuilder(context.Background(), fake.DB(), "users")
+	n, err := sb.CopyFrom(nil, []pageUser{{ID: 1, Name: "zhuan"}, {ID: 2, Name: "akbar"}})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), n)
+}
+
+func TestItCanRejectCopyFromUnsupportedRows(t *testing.T) {
+	sb := builder.NewMysqlBuilder(context.Background(), nil, "users")
+	_, err := sb.CopyFrom(nil, []int{1, 2})
+	assert.Error(t, err)
+	_, err = sb.CopyFrom(nil, [][]any{{1}})
+	assert.Error(t, err)
+}
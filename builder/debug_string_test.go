@@ -0,0 +1,58 @@
+package builder_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	builder "github.com/zhuan69/go-simple-sql-builder/builder"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestItCanGenerateDebugStringPgsql(t *testing.T) {
+	createdAt := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+	builder := builder.NewPgsqlBuilder(context.Background(), nil, "users")
+	builder.Select([]string{"id"}).
+		Where("name", "O'Brien").
+		Where("created_at", createdAt).
+		Where("avatar", []byte{0xde, 0xad}).
+		Where("deleted_at", nil).
+		Where("active", true).
+		OrWhere("age", 30).
+		OrderBy("id", "asc")
+	assert.Equal(t, `SELECT id FROM users WHERE name='O''Brien' AND created_at='2023-01-02 03:04:05+00:00'`+
+		` AND avatar='\xDEAD' AND deleted_at=NULL AND active=TRUE OR age=30 ORDER BY id asc`, builder.ToDebugString())
+	assert.Equal(t, "SELECT id FROM users WHERE name=$1 AND created_at=$2 AND avatar=$3 AND deleted_at=$4 AND active=$5 OR age=$6 ORDER BY id asc", builder.ToQueryString())
+}
+
+func TestItCanGenerateDebugStringMysql(t *testing.T) {
+	var nickname *string
+	builder := builder.NewMysqlBuilder(context.Background(), nil, "users")
+	builder.Update(map[string]any{
+		"bio":      `back\slash 'quote'`,
+		"nickname": nickname,
+		"avatar":   []byte{0xbe, 0xef},
+		"score":    sql.NullFloat64{Float64: 1.5, Valid: true},
+	}).Where("id", 7)
+	assert.Equal(t, `UPDATE users SET avatar=X'BEEF',bio='back\\slash ''quote''',nickname=NULL,score=1.5 WHERE id=7`, builder.ToDebugString())
+}
+
+func TestItCanGenerateDebugStringMssql(t *testing.T) {
+	builder := builder.NewMssqlBuilder(context.Background(), nil, "users")
+	builder.Insert(map[string]any{
+		"active": false,
+		"avatar": []byte{0x01},
+		"name":   "zhuan",
+	})
+	assert.Equal(t, "INSERT INTO [users] ([active],[avatar],[name]) VALUES (0,0x01,N'zhuan')", builder.ToDebugString())
+}
+
+func TestItCanGenerateDebugStringOracle(t *testing.T) {
+	builder := builder.NewOracleBuilder(context.Background(), nil, "users")
+	builder.Select([]string{"id"}).
+		Where("created_at", time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)).
+		Where("name", sql.Named("name", "zhuan"))
+	assert.Equal(t, `SELECT "id" FROM "users" WHERE "created_at"=TIMESTAMP '2023-01-02 03:04:05' AND "name"='zhuan'`, builder.ToDebugString())
+}
@@ -0,0 +1,207 @@
+package builder
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// ErrNilConnection is returned when a builder is executed without a connection.
+var ErrNilConnection = errors.New("builder: nil connection")
+
+// SqlQuerier is the read side of *sql.DB, *sql.Tx and *sql.Conn, used by
+// RowQuery and RowsQuery of the database/sql dialects.
+type SqlQuerier interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// SqlExecer is the write side of *sql.DB, *sql.Tx and *sql.Conn, used by Exec.
+type SqlExecer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// SqlExecutor is satisfied by *sql.DB, *sql.Tx and *sql.Conn, so builders
+// of the database/sql dialects can run inside a transaction.
+type SqlExecutor interface {
+	SqlQuerier
+	SqlExecer
+}
+
+// PgxQuerier is the read side of *pgxpool.Pool, pgx.Tx and *pgx.Conn, used
+// by RowQuery and RowsQuery of the pgsql builder.
+type PgxQuerier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// PgxExecer is the write side of *pgxpool.Pool, pgx.Tx and *pgx.Conn, used by Exec.
+type PgxExecer interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+}
+
+// PgxExecutor is satisfied by *pgxpool.Pool, pgx.Tx and *pgx.Conn, so pgsql
+// builders can run inside a transaction.
+type PgxExecutor interface {
+	PgxQuerier
+	PgxExecer
+}
+
+// PgxTxBeginner is satisfied by *pgxpool.Pool, *pgx.Conn and pgx.Tx, the
+// latter starting a savepoint.
+type PgxTxBeginner interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+// PgxBatchSender is satisfied by *pgxpool.Pool, *pgx.Conn and pgx.Tx, it
+// sends a Batch in a single round trip.
+type PgxBatchSender interface {
+	SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults
+}
+
+// PgxCopier is satisfied by *pgxpool.Pool, *pgx.Conn and pgx.Tx, it bulk
+// loads rows with the COPY protocol.
+type PgxCopier interface {
+	CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error)
+}
+
+type pgxTxOptionsBeginner interface {
+	BeginTx(ctx context.Context, txOptions pgx.TxOptions) (pgx.Tx, error)
+}
+
+type sqlTxBeginner interface {
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+}
+
+func isNilConnection(conn any) bool {
+	if conn == nil {
+		return true
+	}
+	rv := reflect.ValueOf(conn)
+	return rv.Kind() == reflect.Pointer && rv.IsNil()
+}
+
+// errRowDB only exists to hand out a *sql.Row carrying an error, since
+// sql.Row can not be built outside database/sql. It is opened on first use
+// so importing the package starts no connection opener.
+var errRowDB = sync.OnceValue(func() *sql.DB {
+	return sql.OpenDB(errRowConnector{})
+})
+
+type rowErrKey struct{}
+
+// errSqlRow returns a *sql.Row whose Scan and Err report err.
+func errSqlRow(ctx context.Context, err error) *sql.Row {
+	return errRowDB().QueryRowContext(context.WithValue(ctx, rowErrKey{}, err), "")
+}
+
+type errRowConnector struct{}
+
+// Connect fails every connection with the error errSqlRow put in ctx, so
+// no connection is ever pooled and each query reaches Connect.
+func (errRowConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	if err, ok := ctx.Value(rowErrKey{}).(error); ok {
+		return nil, err
+	}
+	return nil, ErrNilConnection
+}
+
+func (errRowConnector) Driver() driver.Driver {
+	return errRowDriver{}
+}
+
+type errRowDriver struct{}
+
+func (errRowDriver) Open(string) (driver.Conn, error) {
+	return nil, ErrNilConnection
+}
+
+type errPgxRow struct {
+	err error
+}
+
+func (r errPgxRow) Scan(dest ...any) error {
+	return r.err
+}
+
+func sqlExecCommands(conn SqlQuerier) (
+	func(ctx context.Context, query string, args []any) *sql.Row,
+	func(ctx context.Context, query string, args []any) (*sql.Rows, error),
+	func(ctx context.Context, query string, args []any) (int64, error),
+) {
+	if isNilConnection(conn) {
+		return func(ctx context.Context, query string, args []any) *sql.Row {
+				return errSqlRow(ctx, ErrNilConnection)
+			}, func(ctx context.Context, query string, args []any) (*sql.Rows, error) {
+				return nil, ErrNilConnection
+			}, func(ctx context.Context, query string, args []any) (int64, error) {
+				return 0, ErrNilConnection
+			}
+	}
+	return func(ctx context.Context, query string, args []any) *sql.Row {
+			return conn.QueryRowContext(ctx, query, args...)
+		}, func(ctx context.Context, query string, args []any) (*sql.Rows, error) {
+			return conn.QueryContext(ctx, query, args...)
+		}, func(ctx context.Context, query string, args []any) (int64, error) {
+			execer, ok := conn.(SqlExecer)
+			if !ok {
+				return 0, fmt.Errorf("builder: %T does not implement SqlExecer", conn)
+			}
+			res, err := execer.ExecContext(ctx, query, args...)
+			if err != nil {
+				return 0, err
+			}
+			return res.RowsAffected()
+		}
+}
+
+func pgxExecCommands(conn PgxQuerier) (
+	func(ctx context.Context, query string, args []any) pgx.Row,
+	func(ctx context.Context, query string, args []any) (pgx.Rows, error),
+	func(ctx context.Context, query string, args []any) (int64, error),
+) {
+	if isNilConnection(conn) {
+		return func(ctx context.Context, query string, args []any) pgx.Row {
+				return errPgxRow{err: ErrNilConnection}
+			}, func(ctx context.Context, query string, args []any) (pgx.Rows, error) {
+				return nil, ErrNilConnection
+			}, func(ctx context.Context, query string, args []any) (int64, error) {
+				return 0, ErrNilConnection
+			}
+	}
+	return func(ctx context.Context, query string, args []any) pgx.Row {
+			return conn.QueryRow(ctx, query, args...)
+		}, func(ctx context.Context, query string, args []any) (pgx.Rows, error) {
+			return conn.Query(ctx, query, args...)
+		}, func(ctx context.Context, query string, args []any) (int64, error) {
+			execer, ok := conn.(PgxExecer)
+			if !ok {
+				return 0, fmt.Errorf("builder: %T does not implement PgxExecer", conn)
+			}
+			tag, err := execer.Exec(ctx, query, args...)
+			if err != nil {
+				return 0, err
+			}
+			return tag.RowsAffected(), nil
+		}
+}
+
+// pgxCopyCommand returns nil when conn can not COPY, CopyFrom then falls
+// back to multi-row INSERT.
+func pgxCopyCommand(conn PgxQuerier) func(ctx context.Context, tableName string, columns []string, src CopyFromSource) (int64, error) {
+	copier, ok := conn.(PgxCopier)
+	if !ok || isNilConnection(conn) {
+		return nil
+	}
+	return func(ctx context.Context, tableName string, columns []string, src CopyFromSource) (int64, error) {
+		return copier.CopyFrom(ctx, pgx.Identifier(strings.Split(tableName, ".")), columns, src)
+	}
+}
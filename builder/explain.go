@@ -0,0 +1,257 @@
+package builder
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	constants "github.com/zhuan69/go-simple-sql-builder/constants"
+	observable "github.com/zhuan69/go-simple-sql-builder/observable"
+)
+
+// ExplainOptions configures Explain.
+type ExplainOptions struct {
+	// Analyze runs the query to report the actual rows and time, only
+	// supported by PGSQL and only for SELECT, as it would apply any write.
+	Analyze bool
+	// Buffers adds the buffer usage to an analyzed PGSQL plan.
+	Buffers bool
+}
+
+// PlanNode is a step of a query plan. NodeType is the PGSQL node type, such
+// as "Seq Scan" or "Index Scan", or the MySQL access type, such as "ALL" or
+// "ref".
+type PlanNode struct {
+	NodeType    string
+	Table       string
+	Index       string
+	StartupCost float64
+	TotalCost   float64
+	Rows        float64
+	// ActualRows and ActualTime, in milliseconds, are only set when the plan
+	// is analyzed.
+	ActualRows float64
+	ActualTime float64
+	Children   []PlanNode
+}
+
+// Plan is the parsed output of Explain, Raw keeping the JSON returned by
+// the database.
+type Plan struct {
+	Root PlanNode
+	Raw  json.RawMessage
+}
+
+// Nodes returns every node of the plan, parents first.
+func (p *Plan) Nodes() []PlanNode {
+	var nodes []PlanNode
+	var walk func(n PlanNode)
+	walk = func(n PlanNode) {
+		nodes = append(nodes, n)
+		for _, v := range n.Children {
+			walk(v)
+		}
+	}
+	walk(p.Root)
+	return nodes
+}
+
+// UsesSeqScanOn reports whether table is read with a full scan, a "Seq Scan"
+// node in PGSQL or the "ALL" access type in MySQL.
+func (p *Plan) UsesSeqScanOn(table string) bool {
+	for _, v := range p.Nodes() {
+		if strings.EqualFold(v.Table, table) && (v.NodeType == "Seq Scan" || v.NodeType == "ALL") {
+			return true
+		}
+	}
+	return false
+}
+
+// UsesIndex reports whether a node of the plan reads the index.
+func (p *Plan) UsesIndex(index string) bool {
+	for _, v := range p.Nodes() {
+		if strings.EqualFold(v.Index, index) {
+			return true
+		}
+	}
+	return false
+}
+
+// Explain implements SqlBuilder
+func (sb *sqlBuilder[RT, RWT]) Explain(opts ExplainOptions) (*Plan, error) {
+	prefix, err := explainPrefix(sb.typeSql, opts)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Analyze && !sb.isSelect() {
+		return nil, fmt.Errorf("builder: EXPLAIN ANALYZE runs the statement, it is only allowed on SELECT")
+	}
+	explain := sb.withObserve(sb.SqlObserve)
+	so := observable.NewSqlObserve(constants.RAW_KEY, sb.typeSql, sb.tableName, true, 0, 0)
+	so.SetColumn(prefix + " " + sb.sqlQueryString())
+	so.SetValue(sb.GetArgsValue()...)
+	explain.rawColumns = sb.argColumns()
+	explain.SqlObserve = []observable.SqlObserve{so}
+	explain.paramStyle = ""
+	row, ok := any(explain.RowQuery()).(rowScanner)
+	if !ok {
+		return nil, fmt.Errorf("builder: can not scan the plan of %T", sb)
+	}
+	var raw []byte
+	if err := row.Scan(&raw); err != nil {
+		return nil, err
+	}
+	return parsePlan(sb.typeSql, raw)
+}
+
+func explainPrefix(typeSql observable.SqlType, opts ExplainOptions) (string, error) {
+	switch typeSql {
+	case observable.PGSQL:
+		options := []string{"FORMAT JSON"}
+		if opts.Analyze {
+			options = append(options, "ANALYZE")
+		}
+		if opts.Buffers {
+			options = append(options, "BUFFERS")
+		}
+		return fmt.Sprintf("EXPLAIN (%s)", strings.Join(options, ", ")), nil
+	case observable.MYSQL:
+		if opts.Analyze || opts.Buffers {
+			return "", fmt.Errorf("builder: EXPLAIN ANALYZE in JSON format is not supported by %s", typeSql)
+		}
+		return "EXPLAIN FORMAT=JSON", nil
+	}
+	return "", fmt.Errorf("builder: EXPLAIN in JSON format is not supported by %s", typeSql)
+}
+
+func parsePlan(typeSql observable.SqlType, raw []byte) (*Plan, error) {
+	plan := &Plan{Raw: append(json.RawMessage{}, raw...)}
+	if typeSql == observable.PGSQL {
+		var res []struct {
+			Plan pgPlanNode `json:"Plan"`
+		}
+		if err := json.Unmarshal(raw, &res); err != nil {
+			return nil, fmt.Errorf("builder: can not parse the plan: %w", err)
+		}
+		if len(res) == 0 {
+			return nil, fmt.Errorf("builder: empty plan")
+		}
+		plan.Root = res[0].Plan.toNode()
+		return plan, nil
+	}
+	var res struct {
+		QueryBlock map[string]any `json:"query_block"`
+	}
+	if err := json.Unmarshal(raw, &res); err != nil {
+		return nil, fmt.Errorf("builder: can not parse the plan: %w", err)
+	}
+	if res.QueryBlock == nil {
+		return nil, fmt.Errorf("builder: plan without query_block")
+	}
+	plan.Root = PlanNode{NodeType: "query_block"}
+	if cost, ok := res.QueryBlock["cost_info"].(map[string]any); ok {
+		plan.Root.TotalCost = jsonNumber(cost["query_cost"])
+	}
+	plan.Root.Children = mysqlTables(res.QueryBlock)
+	return plan, nil
+}
+
+type pgPlanNode struct {
+	NodeType        string       `json:"Node Type"`
+	RelationName    string       `json:"Relation Name"`
+	IndexName       string       `json:"Index Name"`
+	StartupCost     float64      `json:"Startup Cost"`
+	TotalCost       float64      `json:"Total Cost"`
+	PlanRows        float64      `json:"Plan Rows"`
+	ActualRows      float64      `json:"Actual Rows"`
+	ActualTotalTime float64      `json:"Actual Total Time"`
+	Plans           []pgPlanNode `json:"Plans"`
+}
+
+func (n pgPlanNode) toNode() PlanNode {
+	node := PlanNode{
+		NodeType:    n.NodeType,
+		Table:       n.RelationName,
+		Index:       n.IndexName,
+		StartupCost: n.StartupCost,
+		TotalCost:   n.TotalCost,
+		Rows:        n.PlanRows,
+		ActualRows:  n.ActualRows,
+		ActualTime:  n.ActualTotalTime,
+	}
+	for _, v := range n.Plans {
+		node.Children = append(node.Children, v.toNode())
+	}
+	return node
+}
+
+// mysqlTables walks a MySQL plan, where tables are nested in operations such
+// as nested_loop or ordering_operation, returning them in plan order.
+func mysqlTables(v any) []PlanNode {
+	var nodes []PlanNode
+	switch val := v.(type) {
+	case map[string]any:
+		if table, ok := val["table"].(map[string]any); ok {
+			nodes = append(nodes, mysqlTable(table))
+		}
+		keys := make([]string, 0, len(val))
+		for k := range val {
+			if k != "table" {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			nodes = append(nodes, mysqlTables(val[k])...)
+		}
+	case []any:
+		for _, child := range val {
+			nodes = append(nodes, mysqlTables(child)...)
+		}
+	}
+	return nodes
+}
+
+func mysqlTable(table map[string]any) PlanNode {
+	node := PlanNode{
+		NodeType: fmt.Sprint(table["access_type"]),
+		Table:    fmt.Sprint(table["table_name"]),
+		Rows:     jsonNumber(table["rows_examined_per_scan"]),
+	}
+	if key, ok := table["key"].(string); ok {
+		node.Index = key
+	}
+	if cost, ok := table["cost_info"].(map[string]any); ok {
+		node.TotalCost = jsonNumber(cost["prefix_cost"])
+	}
+	node.Children = mysqlTables(map[string]any{"materialized_from_subquery": table["materialized_from_subquery"], "attached_subqueries": table["attached_subqueries"]})
+	return node
+}
+
+// jsonNumber reads MySQL numbers, given as JSON numbers or strings.
+func jsonNumber(v any) float64 {
+	switch val := v.(type) {
+	case float64:
+		return val
+	case string:
+		f, _ := strconv.ParseFloat(val, 64)
+		return f
+	}
+	return 0
+}
+
+// isSelect reports whether the builder renders a SELECT and no write.
+func (sb *sqlBuilder[RT, RWT]) isSelect() bool {
+	isSelect := false
+	for _, v := range sb.SqlObserve {
+		switch v.GetCommand() {
+		case constants.SELECT_KEY:
+			isSelect = true
+		case constants.INSERT_KEY, constants.UPDATE_KEY, constants.DELETE_KEY, constants.UPSERT_KEY:
+			return false
+		}
+	}
+	return isSelect
+}
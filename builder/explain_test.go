@@ -0,0 +1,82 @@
+package builder_test
+
+import (
+	"context"
+	"testing"
+
+	builder "github.com/zhuan69/go-simple-sql-builder/builder"
+	observable "github.com/zhuan69/go-simple-sql-builder/observable"
+	fakedb "github.com/zhuan69/go-simple-sql-builder/tests/mocks/fakedb"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const pgsqlPlan = `[{"Plan": {"Node Type": "Nested Loop", "Startup Cost": 0.29, "Total Cost": 25.1, "Plan Rows": 5,
+ "Actual Rows": 4, "Actual Total Time": 0.12, "Plans": [
+  {"Node Type": "Seq Scan", "Relation Name": "orders", "Startup Cost": 0.0, "Total Cost": 12.5, "Plan Rows": 5},
+  {"Node Type": "Index Scan", "Relation Name": "users", "Index Name": "users_pkey", "Startup Cost": 0.29, "Total Cost": 2.5, "Plan Rows": 1}
+ ]}, "Planning Time": 0.1, "Execution Time": 0.2}]`
+
+const mysqlPlan = `{"query_block": {"select_id": 1, "cost_info": {"query_cost": "3.20"},
+ "nested_loop": [
+  {"table": {"table_name": "orders", "access_type": "ALL", "rows_examined_per_scan": 10, "cost_info": {"prefix_cost": "1.25"}}},
+  {"table": {"table_name": "users", "access_type": "eq_ref", "key": "PRIMARY", "rows_examined_per_scan": 1, "cost_info": {"prefix_cost": "3.20"}}}
+ ]}}`
+
+func TestItCanExplainQueryPgsql(t *testing.T) {
+	fake := fakedb.New(t, observable.PGSQL)
+	fake.ExpectQuery("EXPLAIN (FORMAT JSON, ANALYZE) SELECT id FROM orders WHERE user_id=?").WithArgs(1).
+		WillReturnRows([]string{"QUERY PLAN"}, []any{pgsqlPlan})
+
+	sb := builder.NewPgsqlBuilder(context.Background(), fake.Pgx(), "orders")
+	plan, err := sb.Select([]string{"id"}).Where("user_id", 1).Explain(builder.ExplainOptions{Analyze: true})
+	require.NoError(t, err)
+	assert.Equal(t, "Nested Loop", plan.Root.NodeType)
+	assert.Equal(t, 25.1, plan.Root.TotalCost)
+	assert.Equal(t, float64(4), plan.Root.ActualRows)
+	require.Len(t, plan.Nodes(), 3)
+	assert.True(t, plan.UsesSeqScanOn("orders"))
+	assert.False(t, plan.UsesSeqScanOn("users"))
+	assert.True(t, plan.UsesIndex("users_pkey"))
+	assert.Equal(t, "SELECT id FROM orders WHERE user_id=$1", sb.ToQueryString())
+}
+
+func TestItCanExplainQueryMysql(t *testing.T) {
+	fake := fakedb.New(t, observable.MYSQL)
+	fake.ExpectQuery("EXPLAIN FORMAT=JSON SELECT id FROM orders WHERE user_id=?").WithArgs(1).
+		WillReturnRows([]string{"EXPLAIN"}, []any{mysqlPlan})
+
+	sb := builder.NewMysqlBuilder(context.Background(), fake.DB(), "orders")
+	plan, err := sb.Select([]string{"id"}).Where("user_id", 1).Explain(builder.ExplainOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 3.2, plan.Root.TotalCost)
+	require.Len(t, plan.Root.Children, 2)
+	assert.Equal(t, builder.PlanNode{NodeType: "ALL", Table: "orders", Rows: 10, TotalCost: 1.25}, plan.Root.Children[0])
+	assert.True(t, plan.UsesSeqScanOn("orders"))
+	assert.False(t, plan.UsesSeqScanOn("users"))
+	assert.True(t, plan.UsesIndex("PRIMARY"))
+}
+
+func TestItCanRejectUnsupportedExplain(t *testing.T) {
+	_, err := builder.NewMysqlBuilder(context.Background(), nil, "orders").Select([]string{"id"}).
+		Explain(builder.ExplainOptions{Analyze: true})
+	assert.Error(t, err)
+	_, err = builder.NewMssqlBuilder(context.Background(), nil, "orders").Select([]string{"id"}).
+		Explain(builder.ExplainOptions{})
+	assert.Error(t, err)
+}
+
+func TestItCanNotExplainAnalyzeWrites(t *testing.T) {
+	ctx := context.Background()
+	for _, sb := range []builder.SqlBuilder[pgx.Row, pgx.Rows]{
+		builder.NewPgsqlBuilder(ctx, nil, "orders").Insert(map[string]any{"id": 1}),
+		builder.NewPgsqlBuilder(ctx, nil, "orders").Update(map[string]any{"status": "paid"}).Where("id", 1),
+		builder.NewPgsqlBuilder(ctx, nil, "orders").Delete().Where("id", 1),
+		builder.NewPgsqlBuilder(ctx, nil, "orders").Upsert(map[string]any{"id": 1}, []string{"id"}),
+	} {
+		_, err := sb.Explain(builder.ExplainOptions{Analyze: true})
+		assert.EqualError(t, err, "builder: EXPLAIN ANALYZE runs the statement, it is only allowed on SELECT")
+	}
+}
@@ -0,0 +1,57 @@
+package builder
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"strings"
+)
+
+// QueryFingerprint identifies a statement whatever its argument values.
+type QueryFingerprint struct {
+	// SQL is the normalized query.
+	SQL string
+	// Hash is a short hex hash of SQL, usable as a metric label.
+	Hash string
+}
+
+var (
+	fingerprintString      = regexp.MustCompile(`'(?:[^']|'')*'`)
+	fingerprintIdentifier  = regexp.MustCompile("\"(\\w+)\"|\\[(\\w+)\\]|`(\\w+)`")
+	fingerprintPlaceholder = regexp.MustCompile(`\$\d+|@p\d+|@\w+|(?:^|[^:]):\w+|\?|\b\d+(?:\.\d+)?\b`)
+	fingerprintInList      = regexp.MustCompile(`(?i)\bIN\s*\(\s*\?(?:\s*,\s*\?)*\s*\)`)
+	fingerprintValues      = regexp.MustCompile(`(\(\?(?:,\?)*\))(?:\s*,\s*\(\?(?:,\?)*\))+`)
+	fingerprintWhitespace  = regexp.MustCompile(`\s+`)
+)
+
+// NormalizeQuery collapses the parts of a query that change between
+// executions: placeholders and literals become ?, IN lists and multi-row
+// VALUES keep a single entry, identifier quotes and whitespace are
+// normalized. The same logical query gives the same result in every dialect.
+func NormalizeQuery(query string) string {
+	query = fingerprintString.ReplaceAllString(query, "?")
+	query = fingerprintIdentifier.ReplaceAllString(query, "$1$2$3")
+	query = fingerprintPlaceholder.ReplaceAllStringFunc(query, func(s string) string {
+		// :name keeps the character matched before it, to tell it from a :: cast
+		if i := strings.IndexByte(s, ':'); i > 0 {
+			return s[:i] + "?"
+		}
+		return "?"
+	})
+	query = fingerprintWhitespace.ReplaceAllString(query, " ")
+	query = fingerprintInList.ReplaceAllString(query, "IN (?)")
+	query = fingerprintValues.ReplaceAllString(query, "$1")
+	return strings.TrimSpace(query)
+}
+
+// FingerprintQuery normalizes query and hashes it.
+func FingerprintQuery(query string) QueryFingerprint {
+	normalized := NormalizeQuery(query)
+	sum := sha256.Sum256([]byte(normalized))
+	return QueryFingerprint{SQL: normalized, Hash: hex.EncodeToString(sum[:8])}
+}
+
+// Fingerprint implements SqlBuilder
+func (sb *sqlBuilder[RT, RWT]) Fingerprint() QueryFingerprint {
+	return FingerprintQuery(sb.sqlQueryString())
+}
@@ -0,0 +1,34 @@
+package builder_test
+
+import (
+	"context"
+	"testing"
+
+	builder "github.com/zhuan69/go-simple-sql-builder/builder"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestItCanNormalizeQueries(t *testing.T) {
+	assert.Equal(t, "SELECT id FROM users WHERE id IN (?) AND name=? LIMIT ?",
+		builder.NormalizeQuery("SELECT id FROM users WHERE id IN ($1,$2, $3) AND name='o''brien'\n\t LIMIT 10"))
+	assert.Equal(t, "SELECT id FROM users WHERE id IN (?) AND name=? LIMIT ?",
+		builder.NormalizeQuery("SELECT id  FROM users WHERE id IN (?) AND name=?   LIMIT 20"))
+	assert.Equal(t, "INSERT INTO users (id,name) VALUES (?,?)",
+		builder.NormalizeQuery("INSERT INTO [users] ([id],[name]) VALUES (@p1,@p2),(@p3,@p4)"))
+	assert.Equal(t, "SELECT a::text FROM t WHERE b=?", builder.NormalizeQuery(`SELECT "a"::text FROM t WHERE b=:name`))
+}
+
+func TestItCanFingerprintSameQueryAcrossDialects(t *testing.T) {
+	ctx := context.Background()
+	mysql := builder.NewMysqlBuilder(ctx, nil, "users").
+		Select([]string{"id", "name"}).Where("active", true).WhereLike("name", "zhu%").OrderBy("id", "asc").Limit(10)
+	pgsql := builder.NewPgsqlBuilder(ctx, nil, "users").
+		Select([]string{"id", "name"}).Where("active", false).WhereLike("name", "akb%").OrderBy("id", "asc").Limit(50)
+
+	fp := mysql.Fingerprint()
+	assert.Equal(t, "SELECT id,name FROM users WHERE active=? AND name LIKE ? ORDER BY id asc LIMIT ?", fp.SQL)
+	assert.Len(t, fp.Hash, 16)
+	assert.Equal(t, fp, pgsql.Fingerprint())
+	assert.NotEqual(t, fp.Hash, builder.NewPgsqlBuilder(ctx, nil, "users").Select([]string{"id"}).Fingerprint().Hash)
+}
@@ -0,0 +1,198 @@
+package builder
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	constants "github.com/zhuan69/go-simple-sql-builder/constants"
+	observable "github.com/zhuan69/go-simple-sql-builder/observable"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Hook is called around every query a builder sends to the database. Before
+// may return a derived context, it is the one used for the query and passed
+// to After. RowsQuery calls After once the rows are returned, before they
+// are read, RowQuery of the pgx builders once the row is scanned and
+// Batch.Send as the result of each builder is read.
+type Hook interface {
+	Before(ctx context.Context, query string, args []any) context.Context
+	After(ctx context.Context, query string, args []any, duration time.Duration, err error)
+}
+
+// HookFuncs adapts a pair of functions to Hook, a nil function is skipped.
+type HookFuncs struct {
+	BeforeFunc func(ctx context.Context, query string, args []any) context.Context
+	AfterFunc  func(ctx context.Context, query string, args []any, duration time.Duration, err error)
+}
+
+func (h HookFuncs) Before(ctx context.Context, query string, args []any) context.Context {
+	if h.BeforeFunc == nil {
+		return ctx
+	}
+	return h.BeforeFunc(ctx, query, args)
+}
+
+func (h HookFuncs) After(ctx context.Context, query string, args []any, duration time.Duration, err error) {
+	if h.AfterFunc != nil {
+		h.AfterFunc(ctx, query, args, duration, err)
+	}
+}
+
+// QueryInfo describes the query running, hooks read it with
+// QueryInfoFromContext.
+type QueryInfo struct {
+	Dialect   observable.SqlType
+	Table     string
+	Operation string
+	// Columns names the column bound to each positional argument, empty for
+	// raw arguments.
+	Columns []string
+	// RowsAffected is set by Exec and CopyFrom before After is called, it is
+	// -1 for queries returning rows.
+	RowsAffected int64
+	// RowsReturned is set by Get, Select and Iter, which call After once
+	// the rows are read. It is -1 otherwise.
+	RowsReturned int64
+}
+
+type queryInfoKey struct{}
+
+// QueryInfoFromContext returns the QueryInfo of the query running, inside
+// Hook Before and After.
+func QueryInfoFromContext(ctx context.Context) (*QueryInfo, bool) {
+	info, ok := ctx.Value(queryInfoKey{}).(*QueryInfo)
+	return info, ok
+}
+
+var globalHooks struct {
+	sync.RWMutex
+	hooks []Hook
+}
+
+// RegisterHook adds hooks called around the queries of every builder,
+// before the hooks of the builder itself.
+func RegisterHook(hooks ...Hook) {
+	globalHooks.Lock()
+	defer globalHooks.Unlock()
+	globalHooks.hooks = append(globalHooks.hooks, hooks...)
+}
+
+// ResetHooks removes every hook added with RegisterHook.
+func ResetHooks() {
+	globalHooks.Lock()
+	defer globalHooks.Unlock()
+	globalHooks.hooks = nil
+}
+
+// WithHooks implements SqlBuilder
+func (sb *sqlBuilder[RT, RWT]) WithHooks(hooks ...Hook) SqlBuilder[RT, RWT] {
+	sb.hooks = append(sb.hooks, hooks...)
+	return sb
+}
+
+// beforeHooks runs Before of every hook and returns the context of the query
+// with the function reporting its result to After.
+func (sb *sqlBuilder[RT, RWT]) beforeHooks(ctx context.Context, query string, args []any) (context.Context, *QueryInfo, func(err error)) {
+	globalHooks.RLock()
+	hooks := append(append([]Hook{}, globalHooks.hooks...), sb.hooks...)
+	globalHooks.RUnlock()
+	info := &QueryInfo{Dialect: sb.typeSql, Table: sb.tableName, Operation: sb.operation(query), RowsAffected: -1, RowsReturned: -1}
+	if len(hooks) == 0 {
+		return ctx, info, func(err error) {}
+	}
+	info.Columns = sb.argColumns()
+	ctx = context.WithValue(ctx, queryInfoKey{}, info)
+	for _, h := range hooks {
+		ctx = h.Before(ctx, query, args)
+	}
+	start := time.Now()
+	return ctx, info, func(err error) {
+		duration := time.Since(start)
+		for i := len(hooks) - 1; i >= 0; i-- {
+			hooks[i].After(ctx, query, args, duration, err)
+		}
+	}
+}
+
+// operation is the statement kind of the builder, read from its base command
+// or from the first keyword of a raw query.
+func (sb *sqlBuilder[RT, RWT]) operation(query string) string {
+	for _, v := range sb.SqlObserve {
+		switch v.GetCommand() {
+		case constants.SELECT_KEY, constants.INSERT_KEY, constants.UPDATE_KEY, constants.DELETE_KEY, constants.UPSERT_KEY:
+			return v.GetCommand()
+		}
+	}
+	if fields := strings.Fields(query); len(fields) > 0 {
+		return strings.ToUpper(fields[0])
+	}
+	return ""
+}
+
+// argColumns returns the column of every argument, the ones of a raw query
+// being taken from the builder it wraps and left empty when unknown.
+func (sb *sqlBuilder[RT, RWT]) argColumns() []string {
+	var columns []string
+	for _, v := range sb.SqlObserve {
+		if !v.IsParameterized() || v.GetValues() == nil {
+			continue
+		}
+		cols := v.GetColumns()
+		for i := range v.GetValues() {
+			if v.GetCommand() == constants.RAW_KEY {
+				column := ""
+				if i < len(sb.rawColumns) {
+					column = sb.rawColumns[i]
+				}
+				columns = append(columns, column)
+				continue
+			}
+			if len(cols) == 0 {
+				columns = append(columns, "")
+				continue
+			}
+			columns = append(columns, cols[i%len(cols)])
+		}
+	}
+	return columns
+}
+
+// hookRow hands the query error of row to after. *sql.Row reports it
+// through Err, a pgx.Row only once it is scanned, so it is wrapped to call
+// after from Scan. No row is not a failure, as for *sql.Row.Err.
+func hookRow[RT any](row RT, after func(err error)) RT {
+	if r, ok := any(row).(interface{ Err() error }); ok {
+		after(r.Err())
+		return row
+	}
+	if r, ok := any(row).(pgx.Row); ok {
+		if hooked, ok := any(&hookedPgxRow{row: r, after: after}).(RT); ok {
+			return hooked
+		}
+	}
+	after(nil)
+	return row
+}
+
+type hookedPgxRow struct {
+	row     pgx.Row
+	after   func(err error)
+	scanned bool
+}
+
+func (r *hookedPgxRow) Scan(dest ...any) error {
+	err := r.row.Scan(dest...)
+	if !r.scanned {
+		r.scanned = true
+		if errors.Is(err, pgx.ErrNoRows) {
+			r.after(nil)
+		} else {
+			r.after(err)
+		}
+	}
+	return err
+}
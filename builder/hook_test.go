@@ -0,0 +1,141 @@
+package builder_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	builder "github.com/zhuan69/go-simple-sql-builder/builder"
+	observable "github.com/zhuan69/go-simple-sql-builder/observable"
+	fakedb "github.com/zhuan69/go-simple-sql-builder/tests/mocks/fakedb"
+	mocks "github.com/zhuan69/go-simple-sql-builder/tests/mocks/postgresql"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type hookKey struct{}
+
+type recordingHook struct {
+	name   string
+	events *[]string
+	infos  []builder.QueryInfo
+	errs   []error
+}
+
+func (h *recordingHook) Before(ctx context.Context, query string, args []any) context.Context {
+	*h.events = append(*h.events, h.name+" before "+query)
+	return context.WithValue(ctx, hookKey{}, h.name)
+}
+
+func (h *recordingHook) After(ctx context.Context, query string, args []any, duration time.Duration, err error) {
+	*h.events = append(*h.events, h.name+" after")
+	info, _ := builder.QueryInfoFromContext(ctx)
+	h.infos = append(h.infos, *info)
+	h.errs = append(h.errs, err)
+}
+
+func TestItCanRunHooksAroundExec(t *testing.T) {
+	defer builder.ResetHooks()
+	var events []string
+	global := &recordingHook{name: "global", events: &events}
+	local := &recordingHook{name: "local", events: &events}
+	builder.RegisterHook(global)
+
+	fake := fakedb.New(t, observable.MYSQL)
+	fake.ExpectQuery("UPDATE users SET password=? WHERE id=?").WithArgs("secret", 1).WillReturnResult(1)
+	sb := builder.NewMysqlBuilder(context.Background(), fake.DB(), "users")
+	sb.Update(map[string]any{"password": "secret"}).Where("id", 1).WithHooks(local)
+	_, err := sb.Exec()
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"global before UPDATE users SET password=? WHERE id=?",
+		"local before UPDATE users SET password=? WHERE id=?",
+		"local after",
+		"global after",
+	}, events)
+	assert.Equal(t, builder.QueryInfo{
+		Dialect:      observable.MYSQL,
+		Table:        "users",
+		Operation:    "UPDATE",
+		Columns:      []string{"password", "id"},
+		RowsAffected: 1,
+		RowsReturned: -1,
+	}, local.infos[0])
+	assert.Nil(t, local.errs[0])
+}
+
+func TestItCanPassHookContextAndError(t *testing.T) {
+	failure := errors.New("connection reset")
+	fake := fakedb.New(t, observable.PGSQL)
+	fake.ExpectQuery("SELECT id FROM users WHERE id=?").WithArgs(1).WillReturnError(failure)
+
+	var hookValue any
+	var hookErr error
+	var info *builder.QueryInfo
+	hook := builder.HookFuncs{
+		BeforeFunc: func(ctx context.Context, query string, args []any) context.Context {
+			return context.WithValue(ctx, hookKey{}, "traced")
+		},
+		AfterFunc: func(ctx context.Context, query string, args []any, duration time.Duration, err error) {
+			hookValue = ctx.Value(hookKey{})
+			hookErr = err
+			info, _ = builder.QueryInfoFromContext(ctx)
+		},
+	}
+	sb := builder.NewPgsqlBuilder(context.Background(), fake.Pgx(), "users")
+	_, err := sb.Select([]string{"id"}).Where("id", 1).WithHooks(hook).RowsQuery()
+	assert.ErrorIs(t, err, failure)
+	assert.Equal(t, "traced", hookValue)
+	assert.ErrorIs(t, hookErr, failure)
+	assert.Equal(t, "SELECT", info.Operation)
+	assert.Equal(t, int64(-1), info.RowsAffected)
+}
+
+func TestItCanPassPgxRowErrorToHooks(t *testing.T) {
+	ctx := context.Background()
+	failure := errors.New("relation \"users\" does not exist")
+	var events []string
+	hook := &recordingHook{name: "local", events: &events}
+	pool := &mocks.MockPgxPool{}
+	pool.On("QueryRow", mock.Anything, "SELECT id FROM users WHERE id=$1", 1).Return(mocks.MockRow{Err: failure})
+
+	row := builder.NewPgsqlBuilder(ctx, pool, "users").Select([]string{"id"}).Where("id", 1).WithHooks(hook).RowQuery()
+	assert.Empty(t, hook.errs, "After waits for Scan")
+	var id int
+	assert.ErrorIs(t, row.Scan(&id), failure)
+	require.Len(t, hook.errs, 1)
+	assert.ErrorIs(t, hook.errs[0], failure)
+	pool.AssertExpectations(t)
+}
+
+func TestItCanResetGlobalHooks(t *testing.T) {
+	var events []string
+	builder.RegisterHook(&recordingHook{name: "global", events: &events})
+	builder.ResetHooks()
+
+	fake := fakedb.New(t, observable.MYSQL)
+	fake.ExpectQuery("DELETE FROM users WHERE id=?").WithArgs(1).WillReturnResult(1)
+	_, err := builder.NewMysqlBuilder(context.Background(), fake.DB(), "users").Delete().Where("id", 1).Exec()
+	require.NoError(t, err)
+	assert.Empty(t, events)
+}
+
+func TestItCanReportRowsReturnedToHooks(t *testing.T) {
+	var events []string
+	hook := &recordingHook{name: "local", events: &events}
+	fake := fakedb.New(t, observable.MYSQL)
+	fake.ExpectQuery("SELECT id,name FROM users").
+		WillReturnRows([]string{"id", "name"}, []any{int64(1), "zhuan"}, []any{int64(2), "akbar"})
+
+	sb := builder.NewMysqlBuilder(context.Background(), fake.DB(), "users")
+	users, err := builder.Select[pageUser](sb.Select([]string{"id", "name"}).WithHooks(hook))
+	require.NoError(t, err)
+	require.Len(t, users, 2)
+	require.Len(t, hook.infos, 1)
+	assert.Equal(t, int64(2), hook.infos[0].RowsReturned)
+	assert.Equal(t, int64(-1), hook.infos[0].RowsAffected)
+}
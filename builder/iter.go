@@ -0,0 +1,61 @@
+package builder
+
+import (
+	"context"
+	"iter"
+)
+
+// Iter streams the builder query, scanning one row at a time into T with the
+// same mapping rules as Get. The query runs when the iteration starts and the
+// rows are closed once it ends, including on an early break. A failing query,
+// scan or cancelled context is yielded once as the error and stops the
+// iteration.
+func Iter[T any, RT any, RWT any](sb SqlBuilder[RT, RWT]) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		ctx := context.Background()
+		if b, ok := sb.(*sqlBuilder[RT, RWT]); ok && b.ctx != nil {
+			ctx = b.ctx
+		}
+		if err := ctx.Err(); err != nil {
+			yield(zero, err)
+			return
+		}
+		rows, done, err := queryScanRows(sb)
+		if err != nil {
+			yield(zero, err)
+			return
+		}
+		var n int64
+		defer func() {
+			rows.Close()
+			done(n, err)
+		}()
+		scan, err := newRowScanner[T](rows)
+		if err != nil {
+			yield(zero, err)
+			return
+		}
+		for rows.Next() {
+			if err = ctx.Err(); err != nil {
+				yield(zero, err)
+				return
+			}
+			var v T
+			if err = scan(&v); err != nil {
+				yield(zero, err)
+				return
+			}
+			n++
+			if !yield(v, nil) {
+				return
+			}
+		}
+		if err = rows.Err(); err == nil {
+			err = ctx.Err()
+		}
+		if err != nil {
+			yield(zero, err)
+		}
+	}
+}
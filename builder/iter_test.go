@@ -0,0 +1,74 @@
+package builder_test
+
+import (
+	"context"
+	"testing"
+
+	builder "github.com/zhuan69/go-simple-sql-builder/builder"
+	mocks "github.com/zhuan69/go-simple-sql-builder/tests/mocks/postgresql"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestItCanIterateRows(t *testing.T) {
+	db, sqlMock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer db.Close()
+	sqlMock.ExpectQuery("SELECT id,name FROM users WHERE active=?").WithArgs(true).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "zhuan").AddRow(2, "akbar")).
+		RowsWillBeClosed()
+
+	sb := builder.NewMysqlBuilder(context.Background(), db, "users")
+	sb.Select([]string{"id", "name"}).Where("active", true)
+	var users []pageUser
+	for user, err := range builder.Iter[pageUser](sb) {
+		require.NoError(t, err)
+		users = append(users, user)
+	}
+	assert.Equal(t, []pageUser{{ID: 1, Name: "zhuan"}, {ID: 2, Name: "akbar"}}, users)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestItCanCloseRowsOnEarlyBreak(t *testing.T) {
+	rows := mocks.NewMockRows([]string{"id"}, []any{int64(1)}, []any{int64(2)}, []any{int64(3)})
+	pool := new(mocks.MockPgxPool)
+	pool.On("Query", mock.Anything, "SELECT id FROM users").Return(rows, nil)
+
+	sb := builder.NewPgsqlBuilder(context.Background(), pool, "users")
+	sb.Select([]string{"id"})
+	var ids []int64
+	for id, err := range builder.Iter[int64](sb) {
+		require.NoError(t, err)
+		ids = append(ids, id)
+		break
+	}
+	assert.Equal(t, []int64{1}, ids)
+	assert.True(t, rows.IsClosed())
+}
+
+func TestItCanStopIterationOnContextCancel(t *testing.T) {
+	rows := mocks.NewMockRows([]string{"id"}, []any{int64(1)}, []any{int64(2)}, []any{int64(3)})
+	pool := new(mocks.MockPgxPool)
+	pool.On("Query", mock.Anything, "SELECT id FROM users").Return(rows, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sb := builder.NewPgsqlBuilder(ctx, pool, "users")
+	sb.Select([]string{"id"})
+	var ids []int64
+	var iterErr error
+	for id, err := range builder.Iter[int64](sb) {
+		if err != nil {
+			iterErr = err
+			continue
+		}
+		ids = append(ids, id)
+		cancel()
+	}
+	assert.Equal(t, []int64{1}, ids)
+	assert.ErrorIs(t, iterErr, context.Canceled)
+	assert.True(t, rows.IsClosed())
+}
@@ -0,0 +1,252 @@
+package builder
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	constants "github.com/zhuan69/go-simple-sql-builder/constants"
+	observable "github.com/zhuan69/go-simple-sql-builder/observable"
+)
+
+// CursorColumn is a keyset column stored in a cursor with its sort
+// direction and the value of the last returned row.
+type CursorColumn struct {
+	Column string
+	Sort   string
+	Value  any
+}
+
+type cursorValue struct {
+	Column string `json:"c"`
+	Sort   string `json:"s"`
+	Type   string `json:"t"`
+	Value  string `json:"v,omitempty"`
+}
+
+// EncodeCursor serializes the keyset columns into an opaque URL safe string,
+// keeping the Go type of every value so it binds the same way once decoded.
+func EncodeCursor(columns ...CursorColumn) (string, error) {
+	values := make([]cursorValue, len(columns))
+	for i, v := range columns {
+		t, val, err := encodeCursorValue(v.Value)
+		if err != nil {
+			return "", fmt.Errorf("builder: can not encode cursor column %q: %w", v.Column, err)
+		}
+		values[i] = cursorValue{Column: v.Column, Sort: v.Sort, Type: t, Value: val}
+	}
+	payload, err := json.Marshal(values)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(payload), nil
+}
+
+func DecodeCursor(cursor string) ([]CursorColumn, error) {
+	payload, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("builder: invalid cursor: %w", err)
+	}
+	var values []cursorValue
+	if err := json.Unmarshal(payload, &values); err != nil {
+		return nil, fmt.Errorf("builder: invalid cursor: %w", err)
+	}
+	if len(values) == 0 {
+		return nil, fmt.Errorf("builder: invalid cursor: no column")
+	}
+	columns := make([]CursorColumn, len(values))
+	for i, v := range values {
+		val, err := decodeCursorValue(v.Type, v.Value)
+		if err != nil {
+			return nil, fmt.Errorf("builder: invalid cursor column %q: %w", v.Column, err)
+		}
+		columns[i] = CursorColumn{Column: v.Column, Sort: v.Sort, Value: val}
+	}
+	return columns, nil
+}
+
+func encodeCursorValue(v any) (string, string, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return "null", "", nil
+		}
+		rv = rv.Elem()
+		v = rv.Interface()
+	}
+	switch val := v.(type) {
+	case nil:
+		return "null", "", nil
+	case time.Time:
+		return "time", val.Format(time.RFC3339Nano), nil
+	case []byte:
+		return "bytes", base64.StdEncoding.EncodeToString(val), nil
+	}
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return "int", strconv.FormatInt(rv.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "uint", strconv.FormatUint(rv.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return "float", strconv.FormatFloat(rv.Float(), 'g', -1, 64), nil
+	case reflect.Bool:
+		return "bool", strconv.FormatBool(rv.Bool()), nil
+	case reflect.String:
+		return "string", rv.String(), nil
+	}
+	return "", "", fmt.Errorf("unsupported type %T", v)
+}
+
+func decodeCursorValue(t string, v string) (any, error) {
+	switch t {
+	case "null":
+		return nil, nil
+	case "time":
+		return time.Parse(time.RFC3339Nano, v)
+	case "bytes":
+		return base64.StdEncoding.DecodeString(v)
+	case "int":
+		return strconv.ParseInt(v, 10, 64)
+	case "uint":
+		return strconv.ParseUint(v, 10, 64)
+	case "float":
+		return strconv.ParseFloat(v, 64)
+	case "bool":
+		return strconv.ParseBool(v)
+	case "string":
+		return v, nil
+	}
+	return nil, fmt.Errorf("unsupported type %q", t)
+}
+
+// SeekAfter implements SqlBuilder
+func (sb *sqlBuilder[RT, RWT]) SeekAfter(cursor string) SqlBuilder[RT, RWT] {
+	return sb.seekAfter(cursor)
+}
+
+// NextCursor implements SqlBuilder
+func (sb *sqlBuilder[RT, RWT]) NextCursor(lastRow any) (string, error) {
+	orders := sb.orderColumns()
+	if len(orders) == 0 {
+		return "", fmt.Errorf("builder: keyset pagination needs OrderBy")
+	}
+	columns := make([]CursorColumn, len(orders))
+	for i, v := range orders {
+		val, err := rowValue(lastRow, v.Column)
+		if err != nil {
+			return "", err
+		}
+		columns[i] = CursorColumn{Column: v.Column, Sort: v.Sort, Value: val}
+	}
+	return EncodeCursor(columns...)
+}
+
+func (sb *sqlBuilder[RT, RWT]) orderColumns() []CursorColumn {
+	var columns []CursorColumn
+	for _, v := range sb.SqlObserve {
+		if v.GetCommand() == constants.ORDER_BY_KEY {
+			columns = append(columns, CursorColumn{Column: v.GetColumns()[0], Sort: fmt.Sprint(v.GetValues()[0])})
+		}
+	}
+	return columns
+}
+
+// seekAfter filters the rows following the cursor, comparing the keyset as
+// a row value where the dialect allows it and expanding it into
+// (a > x OR (a = x AND b > y)) otherwise. The cursor comes from the client,
+// so only its values are used, the columns and directions are the OrderBy
+// ones and the cursor has to name exactly those.
+func (sb *sqlBuilder[RT, RWT]) seekAfter(cursor string) *sqlBuilder[RT, RWT] {
+	orders := sb.orderColumns()
+	if len(orders) == 0 {
+		return sb.registerError(fmt.Errorf("builder: keyset pagination needs OrderBy before SeekAfter"))
+	}
+	columns, err := DecodeCursor(cursor)
+	if err != nil {
+		return sb.registerError(err)
+	}
+	if !sameKeyset(orders, columns) {
+		return sb.registerError(fmt.Errorf("builder: cursor does not match the OrderBy columns"))
+	}
+	sort := make([]string, len(orders))
+	for i, v := range orders {
+		sort[i] = v.Sort
+	}
+	expanded := observable.IsExpandedSeek(sb.typeSql, sort)
+	sb.called = sb.called + 1
+	so := observable.NewSqlObserve(constants.SEEK_KEY, sb.typeSql, sb.tableName, true, sb.count+1, sb.called)
+	so.SetSeek(expanded, sort...)
+	for i, v := range orders {
+		if !expanded {
+			so.SetColumn(v.Column)
+			so.SetValue(columns[i].Value)
+			continue
+		}
+		for j, prev := range orders[:i+1] {
+			so.SetColumn(prev.Column)
+			so.SetValue(columns[j].Value)
+		}
+	}
+	sb.count = sb.count + len(so.GetValues())
+	sb.insertBeforeTail(so)
+	return sb
+}
+
+// insertBeforeTail places a condition ahead of the trailing GROUP BY,
+// ORDER BY and paging clauses so it can be called after OrderBy.
+func (sb *sqlBuilder[RT, RWT]) insertBeforeTail(so observable.SqlObserve) {
+	at := len(sb.SqlObserve)
+	for at > 0 {
+		switch sb.SqlObserve[at-1].GetCommand() {
+		case constants.ORDER_BY_KEY, constants.GROUP_BY_KEY, constants.LIMIT_KEY, constants.LIMIT_BY_KEY, constants.OFFSET_KEY:
+			at--
+			continue
+		}
+		break
+	}
+	sb.SqlObserve = append(sb.SqlObserve[:at], append([]observable.SqlObserve{so}, sb.SqlObserve[at:]...)...)
+}
+
+func sameKeyset(orders []CursorColumn, columns []CursorColumn) bool {
+	if len(orders) != len(columns) {
+		return false
+	}
+	for i, v := range orders {
+		if v.Column != columns[i].Column || v.Sort != columns[i].Sort {
+			return false
+		}
+	}
+	return true
+}
+
+// rowValue reads column from a struct mapped by `db` tags or from a map.
+func rowValue(row any, column string) (any, error) {
+	name := column
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+	rv := reflect.ValueOf(row)
+	for rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	switch rv.Kind() {
+	case reflect.Map:
+		for _, key := range []string{column, name} {
+			if v := rv.MapIndex(reflect.ValueOf(key)); v.IsValid() {
+				return v.Interface(), nil
+			}
+		}
+	case reflect.Struct:
+		if f, ok := getStructMap(rv.Type()).byColumn[name]; ok {
+			if v, ok := fieldValue(rv, f.index); ok {
+				return v.Interface(), nil
+			}
+			return nil, nil
+		}
+	}
+	return nil, fmt.Errorf("builder: missing cursor column %q in %T", column, row)
+}
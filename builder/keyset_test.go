@@ -0,0 +1,108 @@
+package builder_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	builder "github.com/zhuan69/go-simple-sql-builder/builder"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type seekUser struct {
+	ID        int64     `db:"id"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+func TestItCanGenerateSeekAfterTuplePgsql(t *testing.T) {
+	cursor, err := builder.EncodeCursor(
+		builder.CursorColumn{Column: "created_at", Sort: "asc", Value: "2023-01-01"},
+		builder.CursorColumn{Column: "id", Sort: "asc", Value: 10},
+	)
+	require.NoError(t, err)
+	sb := builder.NewPgsqlBuilder(context.Background(), nil, "users")
+	sb.Select([]string{"id", "created_at"}).Where("active", true).
+		OrderBy("created_at", "asc").OrderBy("id", "asc").SeekAfter(cursor).Limit(10)
+	assert.Equal(t, "SELECT id,created_at FROM users WHERE active=$1 AND (created_at,id) > ($2,$3) ORDER BY created_at asc,id asc LIMIT 10", sb.ToQueryString())
+	assert.Equal(t, []any{true, "2023-01-01", int64(10)}, sb.GetArgsValue())
+}
+
+func TestItCanGenerateSeekAfterExpandedMysql(t *testing.T) {
+	cursor, err := builder.EncodeCursor(
+		builder.CursorColumn{Column: "created_at", Sort: "asc", Value: "2023-01-01"},
+		builder.CursorColumn{Column: "id", Sort: "asc", Value: 10},
+	)
+	require.NoError(t, err)
+	sb := builder.NewMysqlBuilder(context.Background(), nil, "users")
+	sb.Select([]string{"id"}).OrderBy("created_at", "asc").OrderBy("id", "asc").SeekAfter(cursor)
+	assert.Equal(t, "SELECT id FROM users WHERE (created_at>? OR (created_at=? AND id>?)) ORDER BY created_at asc,id asc", sb.ToQueryString())
+	assert.Equal(t, []any{"2023-01-01", "2023-01-01", int64(10)}, sb.GetArgsValue())
+}
+
+func TestItCanGenerateSeekAfterMixedDirectionPgsql(t *testing.T) {
+	cursor, err := builder.EncodeCursor(
+		builder.CursorColumn{Column: "score", Sort: "desc", Value: 90},
+		builder.CursorColumn{Column: "id", Sort: "asc", Value: 3},
+	)
+	require.NoError(t, err)
+	sb := builder.NewPgsqlBuilder(context.Background(), nil, "users")
+	sb.Select([]string{"id"}).OrderBy("score", "desc").OrderBy("id", "asc").SeekAfter(cursor)
+	assert.Equal(t, "SELECT id FROM users WHERE (score<$1 OR (score=$2 AND id>$3)) ORDER BY score desc,id asc", sb.ToQueryString())
+}
+
+func TestItCanRejectSeekAfterInvalidCursor(t *testing.T) {
+	sb := builder.NewMysqlBuilder(context.Background(), nil, "users")
+	sb.Select([]string{"id"}).OrderBy("id", "asc").SeekAfter("%%%")
+	assert.Contains(t, sb.ToQueryString(), "invalid cursor")
+
+	cursor, err := builder.EncodeCursor(builder.CursorColumn{Column: "name", Sort: "asc", Value: "a"})
+	require.NoError(t, err)
+	sb = builder.NewMysqlBuilder(context.Background(), nil, "users")
+	sb.Select([]string{"id"}).OrderBy("id", "asc").SeekAfter(cursor)
+	assert.Contains(t, sb.ToQueryString(), "cursor does not match")
+
+	sb = builder.NewMysqlBuilder(context.Background(), nil, "users")
+	sb.Select([]string{"id"}).SeekAfter(cursor)
+	assert.Contains(t, sb.ToQueryString(), "keyset pagination needs OrderBy before SeekAfter")
+}
+
+func TestItCanRejectSeekAfterTamperedCursor(t *testing.T) {
+	for _, column := range []builder.CursorColumn{
+		{Column: "id);DROP TABLE users--", Sort: "asc", Value: 1},
+		{Column: "id", Sort: "asc;DROP TABLE users--", Value: 1},
+	} {
+		cursor, err := builder.EncodeCursor(column)
+		require.NoError(t, err)
+		sb := builder.NewPgsqlBuilder(context.Background(), nil, "users")
+		sb.Select([]string{"id"}).OrderBy("id", "asc").SeekAfter(cursor)
+		query := sb.ToQueryString()
+		assert.Contains(t, query, "cursor does not match")
+		assert.NotContains(t, query, "DROP TABLE")
+		assert.Empty(t, sb.GetArgsValue())
+	}
+}
+
+func TestItCanRoundTripNextCursor(t *testing.T) {
+	created := time.Date(2023, 1, 2, 3, 4, 5, 6, time.UTC)
+	sb := builder.NewPgsqlBuilder(context.Background(), nil, "users")
+	sb.Select([]string{"id"}).OrderBy("created_at", "desc").OrderBy("users.id", "desc")
+	cursor, err := sb.NextCursor(seekUser{ID: 7, CreatedAt: created})
+	require.NoError(t, err)
+
+	columns, err := builder.DecodeCursor(cursor)
+	require.NoError(t, err)
+	assert.Equal(t, []builder.CursorColumn{
+		{Column: "created_at", Sort: "desc", Value: created},
+		{Column: "users.id", Sort: "desc", Value: int64(7)},
+	}, columns)
+
+	next := builder.NewPgsqlBuilder(context.Background(), nil, "users")
+	next.Select([]string{"id"}).OrderBy("created_at", "desc").OrderBy("users.id", "desc").SeekAfter(cursor)
+	assert.Equal(t, "SELECT id FROM users WHERE (created_at,users.id) < ($1,$2) ORDER BY created_at desc,users.id desc", next.ToQueryString())
+	assert.Equal(t, []any{created, int64(7)}, next.GetArgsValue())
+
+	_, err = sb.NextCursor(map[string]any{"created_at": created})
+	assert.Error(t, err)
+}
@@ -0,0 +1,131 @@
+package builder
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+
+	observable "github.com/zhuan69/go-simple-sql-builder/observable"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type namedParameter struct {
+	name  string
+	value any
+}
+
+// NamedParameters implements SqlBuilder
+func (sb *sqlBuilder[RT, RWT]) NamedParameters(style observable.ParamStyle) SqlBuilder[RT, RWT] {
+	if style != observable.NAMED_AT && style != observable.NAMED_COLON && style != observable.NAMED_SQLC {
+		return sb.registerError(fmt.Errorf("builder: unknown named parameter style %q", style))
+	}
+	// pgx.NamedArgs only rewrites @name, any other style would not match the args
+	if sb.typeSql == observable.PGSQL && style != observable.NAMED_AT {
+		return sb.registerError(fmt.Errorf("builder: %s only supports the %s named parameter style", sb.typeSql, observable.NAMED_AT))
+	}
+	// go-sql-driver/mysql rejects sql.NamedArg, only sqlc query files can be rendered
+	if sb.typeSql == observable.MYSQL && style != observable.NAMED_SQLC {
+		return sb.registerError(fmt.Errorf("builder: %s does not support named parameters, only the %s style can be rendered", sb.typeSql, observable.NAMED_SQLC))
+	}
+	sb.paramStyle = style
+	return sb
+}
+
+// namedParameters assigns a name to every bound value, derived from its
+// column or taken from a sql.NamedArg value. Values that share a name and
+// are equal reuse the same parameter, different values get a numeric suffix.
+func (sb *sqlBuilder[RT, RWT]) namedParameters() ([][]string, []namedParameter) {
+	names := make([][]string, len(sb.SqlObserve))
+	var params []namedParameter
+	for i, v := range sb.SqlObserve {
+		if !v.IsParameterized() {
+			continue
+		}
+		columns := v.GetColumns()
+		for j, val := range v.GetValues() {
+			name := ""
+			if named, ok := val.(sql.NamedArg); ok {
+				name, val = named.Name, named.Value
+			} else if j < len(columns) {
+				name = parameterName(columns[j])
+			}
+			if name == "" {
+				name = "p"
+			}
+			name, params = registerNamedParameter(params, name, val)
+			names[i] = append(names[i], name)
+		}
+	}
+	return names, params
+}
+
+func registerNamedParameter(params []namedParameter, name string, value any) (string, []namedParameter) {
+	candidate := name
+	for suffix := 2; ; suffix++ {
+		found := false
+		for _, v := range params {
+			if v.name != candidate {
+				continue
+			}
+			if reflect.DeepEqual(v.value, value) {
+				return candidate, params
+			}
+			found = true
+			break
+		}
+		if !found {
+			return candidate, append(params, namedParameter{name: candidate, value: value})
+		}
+		candidate = fmt.Sprintf("%s_%d", name, suffix)
+	}
+}
+
+// parameterName turns a column such as "u.created_at" into "created_at".
+func parameterName(column string) string {
+	if i := strings.LastIndex(column, "."); i >= 0 {
+		column = column[i+1:]
+	}
+	var name strings.Builder
+	for i, r := range strings.ToLower(column) {
+		isLetter := r == '_' || (r >= 'a' && r <= 'z')
+		isDigit := r >= '0' && r <= '9'
+		if isLetter || (isDigit && i > 0) {
+			name.WriteRune(r)
+			continue
+		}
+		name.WriteRune('_')
+	}
+	return strings.Trim(name.String(), "_")
+}
+
+func (sb *sqlBuilder[RT, RWT]) prepareNamedObserve() []observable.SqlObserve {
+	names, _ := sb.namedParameters()
+	observes := make([]observable.SqlObserve, len(sb.SqlObserve))
+	copy(observes, sb.SqlObserve)
+	for i := range observes {
+		if names[i] != nil {
+			observes[i].SetParameterName(sb.paramStyle, names[i]...)
+		}
+	}
+	return observes
+}
+
+// namedArgs returns the arguments in the shape the driver expects named
+// parameters in: pgx.NamedArgs for PostgreSQL, sql.NamedArg otherwise.
+func (sb *sqlBuilder[RT, RWT]) namedArgs() []any {
+	_, params := sb.namedParameters()
+	if sb.typeSql == observable.PGSQL {
+		args := make(pgx.NamedArgs, len(params))
+		for _, v := range params {
+			args[v.name] = v.value
+		}
+		return []any{args}
+	}
+	args := make([]any, len(params))
+	for i, v := range params {
+		args[i] = sql.Named(v.name, v.value)
+	}
+	return args
+}
@@ -0,0 +1,68 @@
+package builder_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	builder "github.com/zhuan69/go-simple-sql-builder/builder"
+	observable "github.com/zhuan69/go-simple-sql-builder/observable"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestItCanGenerateNamedParametersPgsql(t *testing.T) {
+	builder := builder.NewPgsqlBuilder(context.Background(), nil, "users")
+	builder.NamedParameters(observable.NAMED_AT).
+		Select([]string{"id"}).
+		Where("u.status", "active").
+		OrWhere("status", "pending").
+		WhereLike("name", "%jo%")
+	assert.Equal(t, "SELECT id FROM users WHERE u.status=@status OR status=@status_2 AND name LIKE @name", builder.ToQueryString())
+	assert.Equal(t, []any{pgx.NamedArgs{
+		"status":   "active",
+		"status_2": "pending",
+		"name":     "%jo%",
+	}}, builder.GetArgsValue())
+}
+
+func TestItCanDeduplicateNamedParameters(t *testing.T) {
+	builder := builder.NewMssqlBuilder(context.Background(), nil, "users")
+	builder.NamedParameters(observable.NAMED_AT).
+		Update(map[string]any{"status": "active"}).
+		Where("status", "active").
+		Where("id", sql.Named("user_id", 7))
+	assert.Equal(t, "UPDATE [users] SET [status]=@status WHERE [status]=@status AND [id]=@user_id", builder.ToQueryString())
+	assert.Equal(t, []any{sql.Named("status", "active"), sql.Named("user_id", 7)}, builder.GetArgsValue())
+}
+
+func TestItCanGenerateNamedParametersStyle(t *testing.T) {
+	t.Run("Testing it can generate ':name' parameters", func(t *testing.T) {
+		builder := builder.NewOracleBuilder(context.Background(), nil, "users")
+		builder.NamedParameters(observable.NAMED_COLON).Insert(map[string]any{"id": 1, "name": "jo"})
+		assert.Equal(t, `INSERT INTO "users" ("id","name") VALUES (:id,:name)`, builder.ToQueryString())
+		assert.Equal(t, []any{sql.Named("id", 1), sql.Named("name", "jo")}, builder.GetArgsValue())
+	})
+	t.Run("Testing it can generate 'sqlc.arg' parameters", func(t *testing.T) {
+		builder := builder.NewMysqlBuilder(context.Background(), nil, "users")
+		builder.NamedParameters(observable.NAMED_SQLC).Select([]string{"id"}).Where("created_at", "2023-01-01")
+		assert.Equal(t, "SELECT id FROM users WHERE created_at=sqlc.arg(created_at)", builder.ToQueryString())
+	})
+}
+
+func TestItCanNotUseUnsupportedNamedParametersStylePgsql(t *testing.T) {
+	builder := builder.NewPgsqlBuilder(context.Background(), nil, "users")
+	builder.NamedParameters(observable.NAMED_COLON).Select([]string{"id"}).Where("id", 1)
+	assert.Contains(t, builder.ToQueryString(), "builder: pgsql only supports the @name named parameter style")
+	assert.Equal(t, []any{1}, builder.GetArgsValue())
+}
+
+func TestItCanNotUseExecutableNamedParametersMysql(t *testing.T) {
+	for _, style := range []observable.ParamStyle{observable.NAMED_AT, observable.NAMED_COLON} {
+		builder := builder.NewMysqlBuilder(context.Background(), nil, "users")
+		builder.NamedParameters(style).Select([]string{"id"}).Where("id", 1)
+		assert.Contains(t, builder.ToQueryString(), "builder: mysql does not support named parameters, only the sqlc.arg style can be rendered")
+		assert.Equal(t, []any{1}, builder.GetArgsValue())
+	}
+}
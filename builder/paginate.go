@@ -0,0 +1,122 @@
+package builder
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	constants "github.com/zhuan69/go-simple-sql-builder/constants"
+	observable "github.com/zhuan69/go-simple-sql-builder/observable"
+)
+
+// Page is a page of items returned by Paginate.
+type Page[T any] struct {
+	Items   []T
+	Page    int
+	PerPage int
+	Total   int64
+	Pages   int
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// Paginate counts the rows matched by the builder and fetches the requested
+// page of them. The count query is derived from the same builder without
+// ORDER BY, LIMIT and OFFSET, wrapped in a subquery when it is grouped or
+// distinct. The builder itself is left untouched.
+func Paginate[T any, RT any, RWT any](sb SqlBuilder[RT, RWT], page int, perPage int) (Page[T], error) {
+	res := Page[T]{Page: page, PerPage: perPage}
+	b, ok := sb.(*sqlBuilder[RT, RWT])
+	if !ok {
+		return res, fmt.Errorf("builder: can not paginate %T", sb)
+	}
+	if perPage < 1 {
+		return res, errors.New("builder: perPage must be greater than 0")
+	}
+	if page < 1 {
+		page = 1
+		res.Page = page
+	}
+	count := b.countBuilder()
+	row, ok := any(count.RowQuery()).(rowScanner)
+	if !ok {
+		return res, fmt.Errorf("builder: can not scan the count of %T", sb)
+	}
+	if err := row.Scan(&res.Total); err != nil {
+		return res, err
+	}
+	res.Pages = int((res.Total + int64(perPage) - 1) / int64(perPage))
+	if res.Total == 0 || page > res.Pages {
+		return res, nil
+	}
+	items := b.withObserve(b.withoutPaging())
+	items.paging(constants.LIMIT_KEY, perPage)
+	items.paging(constants.OFFSET_KEY, (page-1)*perPage)
+	var err error
+	res.Items, err = Select[T](SqlBuilder[RT, RWT](items))
+	return res, err
+}
+
+// withoutPaging returns the observes without the LIMIT, OFFSET and FETCH
+// ones, which Paginate replaces by the page bounds.
+func (sb *sqlBuilder[RT, RWT]) withoutPaging() []observable.SqlObserve {
+	observes := make([]observable.SqlObserve, 0, len(sb.SqlObserve))
+	for _, v := range sb.SqlObserve {
+		switch v.GetCommand() {
+		case constants.LIMIT_KEY, constants.OFFSET_KEY, constants.FETCH_KEY:
+			continue
+		}
+		observes = append(observes, v)
+	}
+	return observes
+}
+
+func (sb *sqlBuilder[RT, RWT]) countBuilder() *sqlBuilder[RT, RWT] {
+	observes := make([]observable.SqlObserve, 0, len(sb.SqlObserve))
+	wrap := false
+	for _, v := range sb.withoutPaging() {
+		switch v.GetCommand() {
+		case constants.ORDER_BY_KEY:
+			continue
+		case constants.GROUP_BY_KEY, constants.LIMIT_BY_KEY:
+			wrap = true
+		case constants.SELECT_KEY:
+			for _, col := range v.GetColumns() {
+				if strings.HasPrefix(strings.ToUpper(strings.TrimSpace(col)), "DISTINCT") {
+					wrap = true
+				}
+			}
+		}
+		observes = append(observes, v)
+	}
+	count := sb.withObserve(observes)
+	if wrap {
+		count.wrapCount()
+		return count
+	}
+	for i, v := range count.SqlObserve {
+		if v.GetCommand() == constants.SELECT_KEY {
+			so := observable.NewSqlObserve(constants.SELECT_KEY, sb.typeSql, sb.tableName, false, 0, 0)
+			so.SetColumn("COUNT(*)")
+			count.SqlObserve[i] = so
+		}
+	}
+	return count
+}
+
+// wrapCount replaces the observes by a raw SELECT COUNT(*) over the
+// rendered query, carrying the arguments of the inner query.
+func (sb *sqlBuilder[RT, RWT]) wrapCount() {
+	alias := " AS count_query"
+	if sb.typeSql == observable.ORACLE {
+		alias = " count_query"
+	}
+	so := observable.NewSqlObserve(constants.RAW_KEY, sb.typeSql, sb.tableName, true, 0, 0)
+	so.SetColumn(fmt.Sprintf("SELECT COUNT(*) FROM (%s)%s", sb.sqlQueryString(), alias))
+	so.SetValue(sb.GetArgsValue()...)
+	sb.rawColumns = sb.argColumns()
+	sb.SqlObserve = []observable.SqlObserve{so}
+	sb.paramStyle = ""
+}
@@ -0,0 +1,86 @@
+package builder_test
+
+import (
+	"context"
+	"testing"
+
+	builder "github.com/zhuan69/go-simple-sql-builder/builder"
+	observable "github.com/zhuan69/go-simple-sql-builder/observable"
+	fakedb "github.com/zhuan69/go-simple-sql-builder/tests/mocks/fakedb"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type pageUser struct {
+	ID   int64  `db:"id"`
+	Name string `db:"name"`
+}
+
+func TestItCanPaginateQueryPgsql(t *testing.T) {
+	fake := fakedb.New(t, observable.PGSQL)
+	fake.ExpectQuery("SELECT COUNT(*) FROM users WHERE active=?").WithArgs(true).
+		WillReturnRows([]string{"count"}, []any{int64(25)})
+	fake.ExpectQuery("SELECT id,name FROM users WHERE active=? ORDER BY id asc LIMIT 10 OFFSET 10").WithArgs(true).
+		WillReturnRows([]string{"id", "name"}, []any{int64(11), "zhuan"}, []any{int64(12), "akbar"})
+
+	sb := builder.NewPgsqlBuilder(context.Background(), fake.Pgx(), "users")
+	sb.Select([]string{"id", "name"}).Where("active", true).OrderBy("id", "asc")
+	page, err := builder.Paginate[pageUser](sb, 2, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(25), page.Total)
+	assert.Equal(t, 3, page.Pages)
+	assert.Equal(t, 2, page.Page)
+	assert.Equal(t, []pageUser{{ID: 11, Name: "zhuan"}, {ID: 12, Name: "akbar"}}, page.Items)
+	assert.Equal(t, "SELECT id,name FROM users WHERE active=$1 ORDER BY id asc", sb.ToQueryString())
+}
+
+func TestItCanPaginateQueryWithLimitMysql(t *testing.T) {
+	fake := fakedb.New(t, observable.MYSQL)
+	fake.ExpectQuery("SELECT COUNT(*) FROM users").
+		WillReturnRows([]string{"count"}, []any{int64(25)})
+	fake.ExpectQuery("SELECT id FROM users LIMIT 10 OFFSET 10").
+		WillReturnRows([]string{"id"}, []any{int64(11)})
+
+	sb := builder.NewMysqlBuilder(context.Background(), fake.DB(), "users")
+	sb.Select([]string{"id"}).Limit(5).Offset(3)
+	page, err := builder.Paginate[int64](sb, 2, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{11}, page.Items)
+	assert.Equal(t, "SELECT id FROM users LIMIT 5 OFFSET 3", sb.ToQueryString())
+}
+
+func TestItCanPaginateGroupedQueryMysql(t *testing.T) {
+	fake := fakedb.New(t, observable.MYSQL)
+	fake.ExpectQuery("SELECT COUNT(*) FROM (SELECT name FROM users WHERE active=? GROUP BY name) AS count_query").WithArgs(true).
+		WillReturnRows([]string{"count"}, []any{int64(0)})
+
+	sb := builder.NewMysqlBuilder(context.Background(), fake.DB(), "users")
+	sb.Select([]string{"name"}).Where("active", true).GroupBy("name").OrderBy("name", "asc")
+	page, err := builder.Paginate[string](sb, 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), page.Total)
+	assert.Equal(t, 0, page.Pages)
+	assert.Empty(t, page.Items)
+}
+
+func TestItCanPaginateQueryMssql(t *testing.T) {
+	fake := fakedb.New(t, observable.MSSQL)
+	fake.ExpectQuery("SELECT COUNT(*) FROM [users]").
+		WillReturnRows([]string{"count"}, []any{int64(3)})
+	fake.ExpectQuery("SELECT [id],[name] FROM [users] ORDER BY (SELECT NULL) OFFSET 0 ROWS FETCH NEXT 5 ROWS ONLY").
+		WillReturnRows([]string{"id", "name"}, []any{int64(1), "zhuan"})
+
+	sb := builder.NewMssqlBuilder(context.Background(), fake.DB(), "users")
+	page, err := builder.Paginate[pageUser](sb.Select([]string{"id", "name"}), 0, 5)
+	require.NoError(t, err)
+	assert.Equal(t, 1, page.Page)
+	assert.Equal(t, 1, page.Pages)
+	assert.Len(t, page.Items, 1)
+}
+
+func TestItCanGenerateGroupByQuery(t *testing.T) {
+	sb := builder.NewPgsqlBuilder(context.Background(), nil, "orders")
+	sb.Select([]string{"user_id", "SUM(total) as total"}).GroupBy("user_id")
+	assert.Equal(t, "SELECT user_id,SUM(total) as total FROM orders GROUP BY user_id", sb.ToQueryString())
+}
@@ -0,0 +1,178 @@
+package builder
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// scanRows is the common surface of *sql.Rows and pgx.Rows used to map
+// result sets into structs.
+type scanRows interface {
+	Next() bool
+	Scan(dest ...any) error
+	Err() error
+	Close()
+	Columns() ([]string, error)
+	NoRowsErr() error
+}
+
+type sqlScanRows struct {
+	*sql.Rows
+}
+
+func (r sqlScanRows) Close() {
+	r.Rows.Close()
+}
+
+func (r sqlScanRows) NoRowsErr() error {
+	return sql.ErrNoRows
+}
+
+type pgxScanRows struct {
+	pgx.Rows
+}
+
+func (r pgxScanRows) Columns() ([]string, error) {
+	fields := r.FieldDescriptions()
+	columns := make([]string, len(fields))
+	for i, v := range fields {
+		columns[i] = v.Name
+	}
+	return columns, nil
+}
+
+func (r pgxScanRows) NoRowsErr() error {
+	return pgx.ErrNoRows
+}
+
+func newScanRows(rows any) (scanRows, error) {
+	switch r := rows.(type) {
+	case *sql.Rows:
+		return sqlScanRows{r}, nil
+	case pgx.Rows:
+		return pgxScanRows{r}, nil
+	}
+	return nil, fmt.Errorf("builder: unsupported rows type %T", rows)
+}
+
+// Get runs the builder query and scans the first row into T. Structs are
+// mapped by `db` tag or snake_case field name, other types are scanned as a
+// single column. It returns sql.ErrNoRows or pgx.ErrNoRows when the query
+// has no result.
+func Get[T any, RT any, RWT any](sb SqlBuilder[RT, RWT]) (res T, err error) {
+	rows, done, err := queryScanRows(sb)
+	if err != nil {
+		return res, err
+	}
+	var n int64
+	defer func() {
+		rows.Close()
+		done(n, err)
+	}()
+	scan, err := newRowScanner[T](rows)
+	if err != nil {
+		return res, err
+	}
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return res, err
+		}
+		return res, rows.NoRowsErr()
+	}
+	n = 1
+	if err := scan(&res); err != nil {
+		return res, err
+	}
+	return res, rows.Err()
+}
+
+// Select runs the builder query and scans every row into T, following the
+// same mapping rules as Get.
+func Select[T any, RT any, RWT any](sb SqlBuilder[RT, RWT]) (res []T, err error) {
+	rows, done, err := queryScanRows(sb)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		rows.Close()
+		done(int64(len(res)), err)
+	}()
+	scan, err := newRowScanner[T](rows)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var v T
+		if err := scan(&v); err != nil {
+			return nil, err
+		}
+		res = append(res, v)
+	}
+	return res, rows.Err()
+}
+
+// queryScanRows runs the builder query, done reporting the rows read and
+// the error to the hooks once the scanning ends.
+func queryScanRows[RT any, RWT any](sb SqlBuilder[RT, RWT]) (rows scanRows, done func(n int64, err error), err error) {
+	b, ok := sb.(*sqlBuilder[RT, RWT])
+	if !ok {
+		res, err := sb.RowsQuery()
+		if err != nil {
+			return nil, nil, err
+		}
+		rows, err = newScanRows(res)
+		return rows, func(n int64, err error) {}, err
+	}
+	query, args := b.sqlQueryString(), b.GetArgsValue()
+	ctx, info, after := b.beforeHooks(b.ctx, query, args)
+	res, err := b.execRowsCommand(ctx, query, args)
+	if err == nil {
+		rows, err = newScanRows(res)
+	}
+	if err != nil {
+		after(err)
+		return nil, nil, err
+	}
+	return rows, func(n int64, err error) {
+		info.RowsReturned = n
+		after(err)
+	}, nil
+}
+
+// newRowScanner resolves the destination of every column once, returning a
+// function that scans the current row into a T.
+func newRowScanner[T any](rows scanRows) (func(dest *T) error, error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	if !isNestedStruct(t) || t.Kind() == reflect.Pointer {
+		if len(columns) != 1 {
+			return nil, fmt.Errorf("builder: scanning into %s expects 1 column but got %d", t, len(columns))
+		}
+		return func(dest *T) error {
+			return rows.Scan(dest)
+		}, nil
+	}
+	sm := getStructMap(t)
+	fields := make([]structField, len(columns))
+	for i, v := range columns {
+		field, ok := sm.byColumn[v]
+		if !ok {
+			return nil, fmt.Errorf("builder: missing destination field for column %q in %s", v, t)
+		}
+		fields[i] = field
+	}
+	return func(dest *T) error {
+		v := reflect.ValueOf(dest).Elem()
+		targets := make([]any, len(fields))
+		for i, f := range fields {
+			targets[i] = fieldByIndex(v, f.index).Addr().Interface()
+		}
+		return rows.Scan(targets...)
+	}, nil
+}
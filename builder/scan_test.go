@@ -0,0 +1,91 @@
+package builder_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	builder "github.com/zhuan69/go-simple-sql-builder/builder"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type timestamps struct {
+	CreatedAt time.Time
+	UpdatedAt *time.Time
+}
+
+type scanUser struct {
+	timestamps
+	ID       int64          `db:"id"`
+	FullName string         `db:"name"`
+	Nickname *string        `db:"nickname"`
+	Bio      sql.NullString `db:"bio"`
+	Ignored  string         `db:"-"`
+}
+
+func TestItCanSelectIntoStructs(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer db.Close()
+	createdAt := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+	mock.ExpectQuery("SELECT id,name,nickname,bio,created_at,updated_at FROM users WHERE active=?").
+		WithArgs(true).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "nickname", "bio", "created_at", "updated_at"}).
+			AddRow(1, "Zhuan", "zhu", "gopher", createdAt, createdAt).
+			AddRow(2, "Akbar", nil, nil, createdAt, nil))
+
+	sb := builder.NewMysqlBuilder(context.Background(), db, "users")
+	sb.Select([]string{"id", "name", "nickname", "bio", "created_at", "updated_at"}).Where("active", true)
+	users, err := builder.Select[scanUser](sb)
+	require.NoError(t, err)
+	require.Len(t, users, 2)
+	assert.Equal(t, int64(1), users[0].ID)
+	assert.Equal(t, "Zhuan", users[0].FullName)
+	assert.Equal(t, "zhu", *users[0].Nickname)
+	assert.Equal(t, sql.NullString{String: "gopher", Valid: true}, users[0].Bio)
+	assert.Equal(t, createdAt, users[0].CreatedAt)
+	assert.Equal(t, createdAt, *users[0].UpdatedAt)
+	assert.Nil(t, users[1].Nickname)
+	assert.False(t, users[1].Bio.Valid)
+	assert.Nil(t, users[1].UpdatedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestItCanGetSingleRow(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer db.Close()
+
+	t.Run("Testing it can scan the first row", func(t *testing.T) {
+		mock.ExpectQuery("SELECT name FROM users WHERE id=?").
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("Zhuan"))
+		sb := builder.NewMysqlBuilder(context.Background(), db, "users")
+		sb.Select([]string{"name"}).Where("id", 1)
+		name, err := builder.Get[string](sb)
+		require.NoError(t, err)
+		assert.Equal(t, "Zhuan", name)
+	})
+	t.Run("Testing it return no rows error", func(t *testing.T) {
+		mock.ExpectQuery("SELECT id,name FROM users WHERE id=?").
+			WithArgs(2).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+		sb := builder.NewMysqlBuilder(context.Background(), db, "users")
+		sb.Select([]string{"id", "name"}).Where("id", 2)
+		_, err := builder.Get[scanUser](sb)
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+	})
+	t.Run("Testing it return error on unknown column", func(t *testing.T) {
+		mock.ExpectQuery("SELECT id,email FROM users").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "email"}).AddRow(1, "a@b.c"))
+		sb := builder.NewMysqlBuilder(context.Background(), db, "users")
+		sb.Select([]string{"id", "email"})
+		_, err := builder.Get[scanUser](sb)
+		assert.ErrorContains(t, err, `missing destination field for column "email"`)
+	})
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
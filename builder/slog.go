@@ -0,0 +1,164 @@
+package builder
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// SlogOptions configures the hook returned by NewSlogHook.
+type SlogOptions struct {
+	// Level of successful queries, slog.LevelDebug by default.
+	Level *slog.Level
+	// SlowThreshold logs queries taking at least this long at SlowLevel,
+	// zero disables it.
+	SlowThreshold time.Duration
+	// SlowLevel is slog.LevelWarn by default.
+	SlowLevel *slog.Level
+	// ErrorLevel of failing queries, slog.LevelError by default.
+	ErrorLevel *slog.Level
+	// RedactColumns hide the values of every column or parameter whose name
+	// contains one of them, matched case-insensitively so "token" also hides
+	// access_token and "password" hides users.password_hash. It defaults to
+	// DefaultRedactColumns.
+	RedactColumns []string
+}
+
+// DefaultRedactColumns are redacted when SlogOptions.RedactColumns is nil.
+var DefaultRedactColumns = []string{"password", "token", "secret", "api_key"}
+
+const redacted = "[REDACTED]"
+
+type slogHook struct {
+	logger     *slog.Logger
+	level      slog.Level
+	slow       time.Duration
+	slowLevel  slog.Level
+	errorLevel slog.Level
+	redact     []string
+}
+
+// NewSlogHook returns a Hook logging every query with its dialect, table,
+// operation, SQL, redacted args, duration, rows affected or returned and
+// error.
+func NewSlogHook(logger *slog.Logger, opts SlogOptions) Hook {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	h := &slogHook{
+		logger:     logger,
+		level:      slog.LevelDebug,
+		slow:       opts.SlowThreshold,
+		slowLevel:  slog.LevelWarn,
+		errorLevel: slog.LevelError,
+	}
+	if opts.Level != nil {
+		h.level = *opts.Level
+	}
+	if opts.SlowLevel != nil {
+		h.slowLevel = *opts.SlowLevel
+	}
+	if opts.ErrorLevel != nil {
+		h.errorLevel = *opts.ErrorLevel
+	}
+	columns := opts.RedactColumns
+	if columns == nil {
+		columns = DefaultRedactColumns
+	}
+	for _, v := range columns {
+		if v != "" {
+			h.redact = append(h.redact, strings.ToLower(v))
+		}
+	}
+	return h
+}
+
+func (h *slogHook) Before(ctx context.Context, query string, args []any) context.Context {
+	return ctx
+}
+
+func (h *slogHook) After(ctx context.Context, query string, args []any, duration time.Duration, err error) {
+	level := h.level
+	if h.slow > 0 && duration >= h.slow {
+		level = h.slowLevel
+	}
+	if err != nil {
+		level = h.errorLevel
+	}
+	if !h.logger.Enabled(ctx, level) {
+		return
+	}
+	attrs := make([]slog.Attr, 0, 10)
+	info, ok := QueryInfoFromContext(ctx)
+	if ok {
+		attrs = append(attrs,
+			slog.String("dialect", string(info.Dialect)),
+			slog.String("table", info.Table),
+			slog.String("operation", info.Operation),
+		)
+	}
+	attrs = append(attrs,
+		slog.String("sql", query),
+		slog.Any("args", h.redactArgs(info, args)),
+		slog.Duration("duration", duration),
+	)
+	if ok && info.RowsAffected >= 0 {
+		attrs = append(attrs, slog.Int64("rows_affected", info.RowsAffected))
+	}
+	if ok && info.RowsReturned >= 0 {
+		attrs = append(attrs, slog.Int64("rows_returned", info.RowsReturned))
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+	h.logger.LogAttrs(ctx, level, "query", attrs...)
+}
+
+// isRedacted matches any part of the column, so table prefixes, the "_2"
+// suffix of repeated named parameters and names such as refresh_token are
+// covered as well.
+func (h *slogHook) isRedacted(column string) bool {
+	column = strings.ToLower(column)
+	for _, v := range h.redact {
+		if strings.Contains(column, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactArgs copies args hiding the values bound to a redacted column,
+// named arguments being matched by their name. Values whose column is not
+// known are hidden as well, they could be bound to any column.
+func (h *slogHook) redactArgs(info *QueryInfo, args []any) []any {
+	res := make([]any, len(args))
+	for i, v := range args {
+		switch arg := v.(type) {
+		case sql.NamedArg:
+			if h.isRedacted(arg.Name) {
+				arg.Value = redacted
+			}
+			res[i] = arg
+			continue
+		case pgx.NamedArgs:
+			named := make(pgx.NamedArgs, len(arg))
+			for k, val := range arg {
+				if h.isRedacted(k) {
+					val = redacted
+				}
+				named[k] = val
+			}
+			res[i] = named
+			continue
+		}
+		if info != nil && (i >= len(info.Columns) || info.Columns[i] == "" || h.isRedacted(info.Columns[i])) {
+			v = redacted
+		}
+		res[i] = v
+	}
+	return res
+}
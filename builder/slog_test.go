@@ -0,0 +1,168 @@
+package builder_test
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	builder "github.com/zhuan69/go-simple-sql-builder/builder"
+	observable "github.com/zhuan69/go-simple-sql-builder/observable"
+	fakedb "github.com/zhuan69/go-simple-sql-builder/tests/mocks/fakedb"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func decodeLog(t *testing.T, buf *bytes.Buffer) map[string]any {
+	t.Helper()
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	return entry
+}
+
+func TestItCanLogQueryWithSlog(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	fake := fakedb.New(t, observable.MYSQL)
+	fake.ExpectQuery("UPDATE users SET password=?,token=? WHERE id=?").WillReturnResult(1)
+
+	sb := builder.NewMysqlBuilder(context.Background(), fake.DB(), "users")
+	sb.Update(map[string]any{"password": "hunter2", "token": "abc"}).Where("id", 7).
+		WithHooks(builder.NewSlogHook(logger, builder.SlogOptions{}))
+	_, err := sb.Exec()
+	require.NoError(t, err)
+
+	entry := decodeLog(t, &buf)
+	assert.Equal(t, "DEBUG", entry["level"])
+	assert.Equal(t, "query", entry["msg"])
+	assert.Equal(t, "mysql", entry["dialect"])
+	assert.Equal(t, "users", entry["table"])
+	assert.Equal(t, "UPDATE", entry["operation"])
+	assert.Equal(t, "UPDATE users SET password=?,token=? WHERE id=?", entry["sql"])
+	assert.Equal(t, []any{"[REDACTED]", "[REDACTED]", float64(7)}, entry["args"])
+	assert.Equal(t, float64(1), entry["rows_affected"])
+	assert.NotContains(t, buf.String(), "hunter2")
+}
+
+func TestItCanLogSlowAndFailingQueriesWithSlog(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	slow := builder.HookFuncs{}
+	hook := builder.NewSlogHook(logger, builder.SlogOptions{SlowThreshold: time.Nanosecond, RedactColumns: []string{"email"}})
+
+	fake := fakedb.New(t, observable.PGSQL)
+	fake.ExpectQuery("SELECT id FROM users WHERE email=?").WillReturnRows([]string{"id"})
+	fake.ExpectQuery("DELETE FROM users WHERE email=?").WillReturnError(errors.New("permission denied"))
+
+	_, err := builder.NewPgsqlBuilder(context.Background(), fake.Pgx(), "users").
+		Select([]string{"id"}).Where("email", "a@b.c").WithHooks(slow, hook).RowsQuery()
+	require.NoError(t, err)
+	entry := decodeLog(t, &buf)
+	assert.Equal(t, "WARN", entry["level"])
+	assert.Equal(t, []any{"[REDACTED]"}, entry["args"])
+	assert.NotContains(t, entry, "rows_affected")
+
+	buf.Reset()
+	_, err = builder.NewPgsqlBuilder(context.Background(), fake.Pgx(), "users").
+		Delete().Where("email", "a@b.c").WithHooks(hook).Exec()
+	require.Error(t, err)
+	entry = decodeLog(t, &buf)
+	assert.Equal(t, "ERROR", entry["level"])
+	assert.Equal(t, "permission denied", entry["error"])
+}
+
+func TestItCanSkipQueriesBelowSlogLevel(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	fake := fakedb.New(t, observable.MYSQL)
+	fake.ExpectQuery("DELETE FROM users WHERE id=?").WillReturnResult(1)
+
+	_, err := builder.NewMysqlBuilder(context.Background(), fake.DB(), "users").
+		Delete().Where("id", 1).WithHooks(builder.NewSlogHook(logger, builder.SlogOptions{})).Exec()
+	require.NoError(t, err)
+	assert.Empty(t, buf.String())
+}
+
+func TestItCanRedactNamedArgsWithSlog(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	fake := fakedb.New(t, observable.PGSQL)
+	fake.ExpectQueryRegexp("^UPDATE users").WillReturnResult(1)
+
+	_, err := builder.NewPgsqlBuilder(context.Background(), fake.Pgx(), "users").
+		Update(map[string]any{"password": "hunter2"}).Where("password", "old").
+		NamedParameters(observable.NAMED_AT).WithHooks(builder.NewSlogHook(logger, builder.SlogOptions{})).Exec()
+	require.NoError(t, err)
+	assert.NotContains(t, buf.String(), "hunter2")
+	assert.NotContains(t, buf.String(), "old")
+}
+
+func TestItCanRedactPaginateArgsWithSlog(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	fake := fakedb.New(t, observable.MYSQL)
+	fake.ExpectQuery("SELECT COUNT(*) FROM (SELECT name FROM users WHERE token=? AND active=? GROUP BY name) AS count_query").
+		WithArgs("abc", true).WillReturnRows([]string{"count"}, []any{int64(1)})
+	fake.ExpectQuery("SELECT name FROM users WHERE token=? AND active=? GROUP BY name LIMIT 10 OFFSET 0").
+		WithArgs("abc", true).WillReturnRows([]string{"name"}, []any{"zhuan"})
+
+	sb := builder.NewMysqlBuilder(context.Background(), fake.DB(), "users")
+	sb.Select([]string{"name"}).Where("token", "abc").Where("active", true).GroupBy("name").
+		WithHooks(builder.NewSlogHook(logger, builder.SlogOptions{}))
+	_, err := builder.Paginate[string](sb, 1, 10)
+	require.NoError(t, err)
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	require.Len(t, lines, 2)
+	for _, line := range lines {
+		entry := decodeLog(t, bytes.NewBuffer(line))
+		assert.Equal(t, []any{"[REDACTED]", true}, entry["args"])
+	}
+	assert.NotContains(t, buf.String(), "abc")
+}
+
+func TestItCanRedactPrefixedAndSuffixedColumnsWithSlog(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	fake := fakedb.New(t, observable.MYSQL)
+	fake.ExpectQuery("UPDATE users SET api_token=?,password_hash=?,refresh_token=? WHERE users.access_token=? AND id=?").
+		WillReturnResult(1)
+
+	_, err := builder.NewMysqlBuilder(context.Background(), fake.DB(), "users").
+		Update(map[string]any{"refresh_token": "r1", "password_hash": "h1", "api_token": "a1"}).
+		Where("users.access_token", "t1").Where("id", 7).
+		WithHooks(builder.NewSlogHook(logger, builder.SlogOptions{})).Exec()
+	require.NoError(t, err)
+
+	entry := decodeLog(t, &buf)
+	assert.Equal(t, []any{"[REDACTED]", "[REDACTED]", "[REDACTED]", "[REDACTED]", float64(7)}, entry["args"])
+	for _, v := range []string{"r1", "h1", "a1", "t1"} {
+		assert.NotContains(t, buf.String(), `"`+v+`"`)
+	}
+}
+
+func TestItCanRedactSqlAndPgxNamedArgsWithSlog(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	hook := builder.NewSlogHook(logger, builder.SlogOptions{})
+
+	hook.After(context.Background(), "UPDATE users SET refresh_token=@refresh_token WHERE id=@id",
+		[]any{sql.Named("refresh_token", "r1"), sql.Named("id", 7)}, time.Millisecond, nil)
+	entry := decodeLog(t, &buf)
+	assert.Equal(t, []any{
+		map[string]any{"Name": "refresh_token", "Value": "[REDACTED]"},
+		map[string]any{"Name": "id", "Value": float64(7)},
+	}, entry["args"])
+
+	buf.Reset()
+	hook.After(context.Background(), "UPDATE users SET password_hash=@password_hash_2 WHERE id=@id",
+		[]any{pgx.NamedArgs{"password_hash_2": "h1", "id": 7}}, time.Millisecond, nil)
+	entry = decodeLog(t, &buf)
+	assert.Equal(t, []any{map[string]any{"password_hash_2": "[REDACTED]", "id": float64(7)}}, entry["args"])
+}
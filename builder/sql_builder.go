@@ -4,34 +4,72 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"sort"
 	"strings"
+	"sync"
 
 	constants "github.com/zhuan69/go-simple-sql-builder/constants"
 	observable "github.com/zhuan69/go-simple-sql-builder/observable"
 
 	"github.com/jackc/pgx/v5"
-	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type sqlBuilder[RT any, RWT any] struct {
 	count           int
 	called          int
+	prewhereCalled  int
+	orderCalled     int
+	requireWhere    bool
+	rawColumns      []string
+	paramStyle      observable.ParamStyle
 	tableName       string
 	typeSql         observable.SqlType
-	connection      any
 	ctx             context.Context
 	SqlObserve      []observable.SqlObserve
-	args            []any
-	execRowCommand  func() RT
-	execRowsCommand func() (res RWT, err error)
+	execRowCommand  func(ctx context.Context, query string, args []any) RT
+	execRowsCommand func(ctx context.Context, query string, args []any) (RWT, error)
+	execCommand     func(ctx context.Context, query string, args []any) (int64, error)
+	execCopyCommand func(ctx context.Context, tableName string, columns []string, src CopyFromSource) (int64, error)
+	hooks           []Hook
 }
 
 func (sb *sqlBuilder[RT, RWT]) RowQuery() RT {
-	return sb.execRowCommand()
+	query, args := sb.sqlQueryString(), sb.GetArgsValue()
+	ctx, _, after := sb.beforeHooks(sb.ctx, query, args)
+	return hookRow(sb.execRowCommand(ctx, query, args), after)
 }
 
 func (sb *sqlBuilder[RT, RWT]) RowsQuery() (res RWT, err error) {
-	return sb.execRowsCommand()
+	query, args := sb.sqlQueryString(), sb.GetArgsValue()
+	ctx, _, after := sb.beforeHooks(sb.ctx, query, args)
+	res, err = sb.execRowsCommand(ctx, query, args)
+	after(err)
+	return res, err
+}
+
+// Exec runs the query without returning rows and reports the rows affected.
+func (sb *sqlBuilder[RT, RWT]) Exec() (int64, error) {
+	query, args := sb.sqlQueryString(), sb.GetArgsValue()
+	ctx, info, after := sb.beforeHooks(sb.ctx, query, args)
+	n, err := sb.execCommand(ctx, query, args)
+	info.RowsAffected = n
+	after(err)
+	return n, err
+}
+
+// Delete implements SqlBuilder
+func (sb *sqlBuilder[RT, RWT]) Delete() SqlBuilder[RT, RWT] {
+	return sb.delete()
+}
+
+// Final implements SqlBuilder
+func (sb *sqlBuilder[RT, RWT]) Final() SqlBuilder[RT, RWT] {
+	return sb.final()
+}
+
+// GroupBy implements SqlBuilder
+func (sb *sqlBuilder[RT, RWT]) GroupBy(col ...string) SqlBuilder[RT, RWT] {
+	return sb.groupBy(col)
 }
 
 // Insert implements SqlBuilder
@@ -53,6 +91,36 @@ func (sb *sqlBuilder[RT, RWT]) OrderBy(column string, sort string) SqlBuilder[RT
 	return sb.orderBy(column, sort)
 }
 
+// Limit implements SqlBuilder
+func (sb *sqlBuilder[RT, RWT]) Limit(limit int) SqlBuilder[RT, RWT] {
+	return sb.paging(constants.LIMIT_KEY, limit)
+}
+
+// LimitBy implements SqlBuilder
+func (sb *sqlBuilder[RT, RWT]) LimitBy(limit int, col ...string) SqlBuilder[RT, RWT] {
+	return sb.limitBy(limit, col)
+}
+
+// Offset implements SqlBuilder
+func (sb *sqlBuilder[RT, RWT]) Offset(offset int) SqlBuilder[RT, RWT] {
+	return sb.paging(constants.OFFSET_KEY, offset)
+}
+
+// Prewhere implements SqlBuilder
+func (sb *sqlBuilder[RT, RWT]) Prewhere(column string, value any) SqlBuilder[RT, RWT] {
+	return sb.prewhere(column, value)
+}
+
+// Returning implements SqlBuilder
+func (sb *sqlBuilder[RT, RWT]) Returning(col ...string) SqlBuilder[RT, RWT] {
+	return sb.returning(col)
+}
+
+// Sample implements SqlBuilder
+func (sb *sqlBuilder[RT, RWT]) Sample(ratio float64) SqlBuilder[RT, RWT] {
+	return sb.sample(ratio)
+}
+
 // Select implements SqlBuilder
 func (sb *sqlBuilder[RT, RWT]) Select(col []string) SqlBuilder[RT, RWT] {
 	return sb.selectSql(col)
@@ -63,11 +131,31 @@ func (sb *sqlBuilder[RT, RWT]) ToQueryString() string {
 	return sb.sqlQueryString()
 }
 
+// ToDebugString implements SqlBuilder
+func (sb *sqlBuilder[RT, RWT]) ToDebugString() string {
+	return sb.sqlDebugString()
+}
+
+// InsertStruct implements SqlBuilder
+func (sb *sqlBuilder[RT, RWT]) InsertStruct(v any) SqlBuilder[RT, RWT] {
+	return sb.insertStruct(v)
+}
+
+// UpdateStruct implements SqlBuilder
+func (sb *sqlBuilder[RT, RWT]) UpdateStruct(v any, opts StructOptions) SqlBuilder[RT, RWT] {
+	return sb.updateStruct(v, opts)
+}
+
 // Update implements SqlBuilder
 func (sb *sqlBuilder[RT, RWT]) Update(colAndVal map[string]any) SqlBuilder[RT, RWT] {
 	return sb.update(colAndVal)
 }
 
+// Upsert implements SqlBuilder
+func (sb *sqlBuilder[RT, RWT]) Upsert(colAndVal map[string]any, conflictColumns []string) SqlBuilder[RT, RWT] {
+	return sb.upsert(colAndVal, conflictColumns)
+}
+
 // Where implements SqlBuilder
 func (sb *sqlBuilder[RT, RWT]) Where(column string, value any) SqlBuilder[RT, RWT] {
 	return sb.where(column, value)
@@ -79,56 +167,72 @@ func (sb *sqlBuilder[RT, RWT]) WhereLike(column string, value any) SqlBuilder[RT
 
 // GetArgsValue implements SqlBuilder
 func (sb *sqlBuilder[RT, RWT]) GetArgsValue() []any {
-	sb.setValues()
-	return sb.args
+	return sb.argsValue()
 }
 
 type SqlBuilder[RT any, RWT any] interface {
 	RowQuery() RT
 	RowsQuery() (res RWT, err error)
+	Exec() (int64, error)
+	CopyFrom(columns []string, rows any) (int64, error)
 	ToQueryString() string
+	// ToDebugString renders the query with every argument escaped and inlined
+	// for logging or copy-pasting into a SQL console. Never execute it,
+	// use ToQueryString with GetArgsValue instead.
+	ToDebugString() string
+	Fingerprint() QueryFingerprint
+	Explain(opts ExplainOptions) (*Plan, error)
 	Insert(colAndVal map[string]any) SqlBuilder[RT, RWT]
 	Update(colAndVal map[string]any) SqlBuilder[RT, RWT]
+	Upsert(colAndVal map[string]any, conflictColumns []string) SqlBuilder[RT, RWT]
+	InsertStruct(v any) SqlBuilder[RT, RWT]
+	UpdateStruct(v any, opts StructOptions) SqlBuilder[RT, RWT]
+	Delete() SqlBuilder[RT, RWT]
 	Where(column string, value any) SqlBuilder[RT, RWT]
 	WhereLike(column string, value any) SqlBuilder[RT, RWT]
 	OrWhere(column string, value any) SqlBuilder[RT, RWT]
 	OrderBy(column string, sort string) SqlBuilder[RT, RWT]
+	GroupBy(col ...string) SqlBuilder[RT, RWT]
+	SeekAfter(cursor string) SqlBuilder[RT, RWT]
+	NextCursor(lastRow any) (string, error)
 	Select(col []string) SqlBuilder[RT, RWT]
+	Limit(limit int) SqlBuilder[RT, RWT]
+	Offset(offset int) SqlBuilder[RT, RWT]
+	Returning(col ...string) SqlBuilder[RT, RWT]
+	Final() SqlBuilder[RT, RWT]
+	Sample(ratio float64) SqlBuilder[RT, RWT]
+	Prewhere(column string, value any) SqlBuilder[RT, RWT]
+	LimitBy(limit int, col ...string) SqlBuilder[RT, RWT]
 	JoinTable(joinType string, table string, conditional string) SqlBuilder[RT, RWT]
+	NamedParameters(style observable.ParamStyle) SqlBuilder[RT, RWT]
 	GetArgsValue() []any
+	Clone() SqlBuilder[RT, RWT]
+	Compile() *Template[RT, RWT]
+	WithHooks(hooks ...Hook) SqlBuilder[RT, RWT]
 }
 
-func NewMysqlBuilder(ctx context.Context, conn *sql.DB, tableName string) SqlBuilder[*sql.Row, *sql.Rows] {
-	sb := &sqlBuilder[*sql.Row, *sql.Rows]{
-		tableName:  tableName,
-		typeSql:    observable.MYSQL,
-		connection: conn,
-		ctx:        ctx,
-	}
-	sb.execRowCommand = func() *sql.Row {
-		return sb.connection.(*sql.DB).QueryRowContext(sb.ctx, sb.sqlQueryString(), sb.args...)
-	}
-	sb.execRowsCommand = func() (res *sql.Rows, err error) {
-		res, err = sb.connection.(*sql.DB).QueryContext(sb.ctx, sb.sqlQueryString(), sb.args...)
-		return res, err
-	}
-	return sb
+func NewMysqlBuilder(ctx context.Context, conn SqlQuerier, tableName string) SqlBuilder[*sql.Row, *sql.Rows] {
+	return newSqlBuilder(ctx, observable.MYSQL, conn, tableName)
 }
 
-func NewPgsqlBuilder(ctx context.Context, conn *pgxpool.Pool, tableName string) SqlBuilder[pgx.Row, pgx.Rows] {
+func NewPgsqlBuilder(ctx context.Context, conn PgxQuerier, tableName string) SqlBuilder[pgx.Row, pgx.Rows] {
 	sb := &sqlBuilder[pgx.Row, pgx.Rows]{
-		tableName:  tableName,
-		typeSql:    observable.PGSQL,
-		connection: conn,
-		ctx:        ctx,
+		tableName: tableName,
+		typeSql:   observable.PGSQL,
+		ctx:       ctx,
 	}
-	sb.execRowCommand = func() pgx.Row {
-		return sb.connection.(*pgxpool.Pool).QueryRow(sb.ctx, sb.sqlQueryString(), sb.args...)
-	}
-	sb.execRowsCommand = func() (res pgx.Rows, err error) {
-		res, err = sb.connection.(*pgxpool.Pool).Query(sb.ctx, sb.sqlQueryString(), sb.args...)
-		return res, err
+	sb.execRowCommand, sb.execRowsCommand, sb.execCommand = pgxExecCommands(conn)
+	sb.execCopyCommand = pgxCopyCommand(conn)
+	return sb
+}
+
+func newSqlBuilder(ctx context.Context, typeSql observable.SqlType, conn SqlQuerier, tableName string) *sqlBuilder[*sql.Row, *sql.Rows] {
+	sb := &sqlBuilder[*sql.Row, *sql.Rows]{
+		tableName: tableName,
+		typeSql:   typeSql,
+		ctx:       ctx,
 	}
+	sb.execRowCommand, sb.execRowsCommand, sb.execCommand = sqlExecCommands(conn)
 	return sb
 }
 
@@ -142,20 +246,115 @@ func (sb *sqlBuilder[RT, RWT]) whereLike(column string, value any) *sqlBuilder[R
 	return sb
 }
 
+// maxPooledQuerySize keeps the occasional huge statement, such as a bulk
+// insert, from pinning its buffer in the pool.
+const maxPooledQuerySize = 64 << 10
+
+var queryBufferPool = sync.Pool{
+	New: func() any {
+		buf := make([]byte, 0, 256)
+		return &buf
+	},
+}
+
 func (sb *sqlBuilder[RT, RWT]) sqlQueryString() string {
-	var query strings.Builder
-	for _, v := range sb.SqlObserve {
-		query.WriteString(v.GetQuery())
+	return sb.renderQuery(false)
+}
+
+func (sb *sqlBuilder[RT, RWT]) sqlDebugString() string {
+	return sb.renderQuery(true)
+}
+
+// renderQuery writes every observe into one pooled buffer grown to the
+// estimated statement size, so the only allocation left is the result.
+func (sb *sqlBuilder[RT, RWT]) renderQuery(literal bool) string {
+	observes := sb.prepareObserve()
+	size := 0
+	for i := range observes {
+		size += observes[i].SizeHint()
+	}
+	pooled := queryBufferPool.Get().(*[]byte)
+	buf := (*pooled)[:0]
+	if cap(buf) < size {
+		buf = make([]byte, 0, size)
+	}
+	for _, v := range observes {
+		if literal {
+			v.SetLiteral()
+		}
+		buf = v.AppendQuery(buf)
+	}
+	query := string(buf)
+	if cap(buf) <= maxPooledQuerySize {
+		*pooled = buf
+		queryBufferPool.Put(pooled)
+	}
+	return query
+}
+
+// prepareObserve returns the observes in the order they have to be rendered,
+// letting dialects that cannot express a clause at its call position move it.
+func (sb *sqlBuilder[RT, RWT]) prepareObserve() []observable.SqlObserve {
+	observes := sb.SqlObserve
+	if sb.paramStyle != "" {
+		observes = sb.prepareNamedObserve()
+	}
+	if err := sb.whereError(observes); err != nil {
+		observes = append(observes[:len(observes):len(observes)], sb.errorObserve(err))
+	}
+	switch sb.typeSql {
+	case observable.MSSQL:
+		return sb.prepareMssqlObserve(observes)
+	case observable.ORACLE:
+		return sb.prepareOracleObserve(observes)
 	}
-	return query.String()
+	return observes
 }
 
-func (sb *sqlBuilder[RT, RWT]) setValues() {
-	for _, v := range sb.SqlObserve {
-		if v.GetValues() != nil {
-			sb.args = append(sb.args, v.GetValues()...)
+// mergeClauseError reports the clauses a MERGE upsert can not be followed
+// by, they would otherwise render into the WHEN NOT MATCHED branch.
+func (sb *sqlBuilder[RT, RWT]) mergeClauseError(observes []observable.SqlObserve) error {
+	isMerge := false
+	for _, v := range observes {
+		switch v.GetCommand() {
+		case constants.UPSERT_KEY:
+			isMerge = true
+		case constants.WHERE_KEY, constants.AND_KEY, constants.OR_KEY, constants.WHERE_LIKE_KEY, constants.SEEK_KEY:
+			if isMerge {
+				return fmt.Errorf("builder: %s can not follow a MERGE upsert on %s", v.GetCommand(), sb.typeSql)
+			}
+		case constants.RETURNING_KEY:
+			// Oracle has no RETURNING for MERGE, SQL Server gets an OUTPUT clause instead
+			if isMerge && sb.typeSql == observable.ORACLE {
+				return fmt.Errorf("builder: %s can not follow a MERGE upsert on %s", v.GetCommand(), sb.typeSql)
+			}
 		}
 	}
+	return nil
+}
+
+// argsValue collects the arguments without touching the builder, so a
+// builder can be rendered from several goroutines.
+func (sb *sqlBuilder[RT, RWT]) argsValue() []any {
+	if sb.paramStyle != "" {
+		return sb.namedArgs()
+	}
+	size := 0
+	for i := range sb.SqlObserve {
+		if sb.SqlObserve[i].IsParameterized() {
+			size += len(sb.SqlObserve[i].GetValues())
+		}
+	}
+	if size == 0 {
+		return nil
+	}
+	args := make([]any, 0, size)
+	for i := range sb.SqlObserve {
+		if sb.SqlObserve[i].IsParameterized() {
+			args = append(args, sb.SqlObserve[i].GetValues()...)
+		}
+	}
+	return args
 }
 
 func (sb *sqlBuilder[RT, RWT]) registerObserve(so observable.SqlObserve) {
@@ -172,16 +371,46 @@ func (sb *sqlBuilder[RT, RWT]) update(colAndVal map[string]any) *sqlBuilder[RT,
 	return sb
 }
 
+func (sb *sqlBuilder[RT, RWT]) delete() *sqlBuilder[RT, RWT] {
+	so := observable.NewSqlObserve(constants.DELETE_KEY, sb.typeSql, sb.tableName, false, 0, 0)
+	sb.registerObserve(so)
+	return sb
+}
+
+func (sb *sqlBuilder[RT, RWT]) upsert(colAndVal map[string]any, conflictColumns []string) *sqlBuilder[RT, RWT] {
+	so := sb.colAndValObserve(constants.UPSERT_KEY, colAndVal)
+	so.SetConflictColumn(conflictColumns...)
+	sb.registerObserve(so)
+	return sb
+}
+
 func (sb *sqlBuilder[RT, RWT]) setColAndValInserUpdate(key string, colAndVal map[string]any) {
+	sb.registerObserve(sb.colAndValObserve(key, colAndVal))
+}
+
+func (sb *sqlBuilder[RT, RWT]) colAndValObserve(key string, colAndVal map[string]any) observable.SqlObserve {
+	columns := make([]string, 0, len(colAndVal))
+	for k := range colAndVal {
+		columns = append(columns, k)
+	}
+	sort.Strings(columns)
+	values := make([]any, len(columns))
+	for i, k := range columns {
+		values[i] = colAndVal[k]
+	}
+	return sb.colsAndValsObserve(key, columns, values)
+}
+
+func (sb *sqlBuilder[RT, RWT]) colsAndValsObserve(key string, columns []string, values []any) observable.SqlObserve {
 	so := observable.NewSqlObserve(key, sb.typeSql, sb.tableName, true, 0, 0)
-	so.SetCalledTime(len(colAndVal))
-	for k, v := range colAndVal {
+	so.SetCalledTime(len(columns))
+	for i, k := range columns {
 		sb.count = sb.count + 1
 		so.SetCounter(sb.count)
 		so.SetColumn(k)
-		so.SetValue(v)
+		so.SetValue(values[i])
 	}
-	sb.registerObserve(so)
+	return so
 }
 
 func (sb *sqlBuilder[RT, RWT]) orWhere(column string, value any) *sqlBuilder[RT, RWT] {
@@ -204,13 +433,21 @@ func (sb *sqlBuilder[RT, RWT]) where(column string, value any) *sqlBuilder[RT, R
 }
 
 func (sb *sqlBuilder[RT, RWT]) orderBy(column string, sort string) *sqlBuilder[RT, RWT] {
-	so := observable.NewSqlObserve(constants.ORDER_BY_KEY, sb.typeSql, sb.tableName, false, 0, 0)
+	sb.orderCalled = sb.orderCalled + 1
+	so := observable.NewSqlObserve(constants.ORDER_BY_KEY, sb.typeSql, sb.tableName, false, 0, sb.orderCalled)
 	so.SetColumn(column)
 	so.SetValue(sort)
 	sb.registerObserve(so)
 	return sb
 }
 
+func (sb *sqlBuilder[RT, RWT]) groupBy(col []string) *sqlBuilder[RT, RWT] {
+	so := observable.NewSqlObserve(constants.GROUP_BY_KEY, sb.typeSql, sb.tableName, false, 0, 0)
+	so.SetColumn(col...)
+	sb.registerObserve(so)
+	return sb
+}
+
 func (sb *sqlBuilder[RT, RWT]) selectSql(col []string) *sqlBuilder[RT, RWT] {
 	so := observable.NewSqlObserve(constants.SELECT_KEY, sb.typeSql, sb.tableName, false, 0, 0)
 	so.SetColumn(col...)
@@ -224,3 +461,51 @@ func (sb *sqlBuilder[RT, RWT]) joinTable(joinType string, table string, conditio
 	sb.registerObserve(so)
 	return sb
 }
+
+func (sb *sqlBuilder[RT, RWT]) paging(key string, value int) *sqlBuilder[RT, RWT] {
+	so := observable.NewSqlObserve(key, sb.typeSql, sb.tableName, false, 0, 0)
+	so.SetValue(value)
+	sb.registerObserve(so)
+	return sb
+}
+
+func (sb *sqlBuilder[RT, RWT]) returning(col []string) *sqlBuilder[RT, RWT] {
+	if len(col) == 0 {
+		col = []string{"*"}
+	}
+	so := observable.NewSqlObserve(constants.RETURNING_KEY, sb.typeSql, sb.tableName, false, 0, 0)
+	so.SetColumn(col...)
+	sb.registerObserve(so)
+	return sb
+}
+
+func (sb *sqlBuilder[RT, RWT]) final() *sqlBuilder[RT, RWT] {
+	so := observable.NewSqlObserve(constants.FINAL_KEY, sb.typeSql, sb.tableName, false, 0, 0)
+	sb.registerObserve(so)
+	return sb
+}
+
+func (sb *sqlBuilder[RT, RWT]) sample(ratio float64) *sqlBuilder[RT, RWT] {
+	so := observable.NewSqlObserve(constants.SAMPLE_KEY, sb.typeSql, sb.tableName, false, 0, 0)
+	so.SetValue(ratio)
+	sb.registerObserve(so)
+	return sb
+}
+
+func (sb *sqlBuilder[RT, RWT]) prewhere(column string, value any) *sqlBuilder[RT, RWT] {
+	sb.count = sb.count + 1
+	sb.prewhereCalled = sb.prewhereCalled + 1
+	so := observable.NewSqlObserve(constants.PREWHERE_KEY, sb.typeSql, sb.tableName, true, sb.count, sb.prewhereCalled)
+	so.SetColumn(column)
+	so.SetValue(value)
+	sb.registerObserve(so)
+	return sb
+}
+
+func (sb *sqlBuilder[RT, RWT]) limitBy(limit int, col []string) *sqlBuilder[RT, RWT] {
+	so := observable.NewSqlObserve(constants.LIMIT_BY_KEY, sb.typeSql, sb.tableName, false, 0, 0)
+	so.SetColumn(col...)
+	so.SetValue(limit)
+	sb.registerObserve(so)
+	return sb
+}
@@ -0,0 +1,12 @@
+package builder
+
+import (
+	"context"
+	"database/sql"
+
+	observable "github.com/zhuan69/go-simple-sql-builder/observable"
+)
+
+func NewClickhouseBuilder(ctx context.Context, conn SqlQuerier, tableName string) SqlBuilder[*sql.Row, *sql.Rows] {
+	return newSqlBuilder(ctx, observable.CLICKHOUSE, conn, tableName)
+}
@@ -0,0 +1,64 @@
+package builder_test
+
+import (
+	"context"
+	"testing"
+
+	builder "github.com/zhuan69/go-simple-sql-builder/builder"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestItCanGenerateSelectQueryClickhouse(t *testing.T) {
+	builder := builder.NewClickhouseBuilder(context.Background(), nil, "events")
+	builder.Select([]string{"user_id", "count() as total"}).
+		Final().
+		Sample(0.1).
+		Prewhere("event_date", "2023-01-01").
+		Prewhere("event_type", "click").
+		Where("country", "ID").
+		OrWhere("country", "SG").
+		OrderBy("total", "desc").
+		LimitBy(5, "user_id").
+		Limit(100)
+	assert.Equal(t, "SELECT user_id,count() as total FROM events FINAL SAMPLE 0.1"+
+		" PREWHERE event_date=? AND event_type=? WHERE country=? OR country=?"+
+		" ORDER BY total desc LIMIT 5 BY user_id LIMIT 100", builder.ToQueryString())
+	assert.Equal(t, []any{"2023-01-01", "click", "ID", "SG"}, builder.GetArgsValue())
+}
+
+func TestItCanGenerateInsertCommandClickhouse(t *testing.T) {
+	builder := builder.NewClickhouseBuilder(context.Background(), nil, "events")
+	builder.Insert(map[string]any{
+		"event_type": "click",
+		"user_id":    123,
+	})
+	assert.Equal(t, "INSERT INTO events (event_type,user_id) VALUES (?,?)", builder.ToQueryString())
+	assert.Equal(t, []any{"click", 123}, builder.GetArgsValue())
+}
+
+func TestItCanGenerateUpdateMutationClickhouse(t *testing.T) {
+	builder := builder.NewClickhouseBuilder(context.Background(), nil, "events")
+	builder.Update(map[string]any{"event_type": "view"}).Where("user_id", 123)
+	assert.Equal(t, "ALTER TABLE events UPDATE event_type=? WHERE user_id=?", builder.ToQueryString())
+	assert.Equal(t, []any{"view", 123}, builder.GetArgsValue())
+}
+
+func TestItCanGenerateDeleteMutationClickhouse(t *testing.T) {
+	builder := builder.NewClickhouseBuilder(context.Background(), nil, "events")
+	builder.Delete().Where("user_id", 123)
+	assert.Equal(t, "ALTER TABLE events DELETE WHERE user_id=?", builder.ToQueryString())
+	assert.Equal(t, []any{123}, builder.GetArgsValue())
+}
+
+func TestItCanNotGenerateClickhouseClauseOnOtherDialect(t *testing.T) {
+	builder := builder.NewPgsqlBuilder(context.Background(), nil, "events")
+	builder.Select([]string{"id"}).Final()
+	assert.Equal(t, "SELECT id FROM events FINAL IS NOT SUPPORTED BY pgsql", builder.ToQueryString())
+}
+
+func TestItCanNotGenerateUpsertClickhouse(t *testing.T) {
+	builder := builder.NewClickhouseBuilder(context.Background(), nil, "events")
+	builder.Upsert(map[string]any{"id": 7, "event_type": "click"}, []string{"id"})
+	assert.Equal(t, "UPSERT IS NOT SUPPORTED BY clickhouse", builder.ToQueryString())
+}
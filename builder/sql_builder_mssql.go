@@ -0,0 +1,77 @@
+package builder
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	constants "github.com/zhuan69/go-simple-sql-builder/constants"
+	observable "github.com/zhuan69/go-simple-sql-builder/observable"
+)
+
+func NewMssqlBuilder(ctx context.Context, conn SqlQuerier, tableName string) SqlBuilder[*sql.Row, *sql.Rows] {
+	return newSqlBuilder(ctx, observable.MSSQL, conn, tableName)
+}
+
+// prepareMssqlObserve rewrites LIMIT/OFFSET into TOP or OFFSET ... FETCH NEXT
+// and moves RETURNING into the OUTPUT clause of the base command, since
+// SQL Server only accepts them at those positions.
+func (sb *sqlBuilder[RT, RWT]) prepareMssqlObserve(observes []observable.SqlObserve) []observable.SqlObserve {
+	prepared := make([]observable.SqlObserve, 0, len(observes)+1)
+	limit, offset := -1, -1
+	hasOrderBy := false
+	var output []string
+	for _, v := range observes {
+		switch v.GetCommand() {
+		case constants.LIMIT_KEY:
+			limit = v.GetValues()[0].(int)
+			continue
+		case constants.OFFSET_KEY:
+			offset = v.GetValues()[0].(int)
+			continue
+		case constants.RETURNING_KEY:
+			output = append(output, v.GetColumns()...)
+			continue
+		case constants.ORDER_BY_KEY:
+			hasOrderBy = true
+		}
+		prepared = append(prepared, v)
+	}
+	isSelect, isMerge := false, false
+	for i := range prepared {
+		switch prepared[i].GetCommand() {
+		case constants.UPSERT_KEY:
+			isMerge = true
+			prepared[i].SetOutput(output...)
+		case constants.SELECT_KEY:
+			isSelect = true
+			if limit >= 0 && offset < 0 {
+				prepared[i].SetTop(limit)
+			}
+		case constants.INSERT_KEY, constants.UPDATE_KEY, constants.DELETE_KEY:
+			prepared[i].SetOutput(output...)
+		}
+	}
+	if !isSelect && (limit >= 0 || offset >= 0) {
+		// dropping the limit would silently touch every matching row
+		return append(prepared, sb.errorObserve(fmt.Errorf("builder: LIMIT and OFFSET are only supported on SELECT by %s", sb.typeSql)))
+	}
+	if err := sb.mergeClauseError(observes); err != nil {
+		return append(prepared, sb.errorObserve(err))
+	}
+	if isMerge {
+		// SQL Server requires MERGE to be terminated, after every other clause
+		so := observable.NewSqlObserve(constants.RAW_KEY, sb.typeSql, sb.tableName, false, 0, 0)
+		so.SetColumn(";")
+		prepared = append(prepared, so)
+	}
+	if offset >= 0 {
+		so := observable.NewSqlObserve(constants.FETCH_KEY, sb.typeSql, sb.tableName, false, 0, 0)
+		if !hasOrderBy {
+			so.SetColumn("(SELECT NULL)")
+		}
+		so.SetValue(offset, limit)
+		prepared = append(prepared, so)
+	}
+	return prepared
+}
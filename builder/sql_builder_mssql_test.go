@@ -0,0 +1,117 @@
+package builder_test
+
+import (
+	"context"
+	"testing"
+
+	builder "github.com/zhuan69/go-simple-sql-builder/builder"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestItCanGenerateInsertCommandMssql(t *testing.T) {
+	builder := builder.NewMssqlBuilder(context.Background(), nil, "testing_table")
+	builder.Insert(map[string]any{
+		"testing_1": "testing",
+		"testing_2": "testing_2",
+		"testing_3": 123,
+	})
+	assert.Equal(t, "INSERT INTO [testing_table] ([testing_1],[testing_2],[testing_3]) VALUES (@p1,@p2,@p3)", builder.ToQueryString())
+	assert.Equal(t, []any{"testing", "testing_2", 123}, builder.GetArgsValue())
+}
+
+func TestItCanGenerateInsertOutputCommandMssql(t *testing.T) {
+	builder := builder.NewMssqlBuilder(context.Background(), nil, "testing_table")
+	builder.Insert(map[string]any{
+		"testing_1": "testing",
+	}).Returning()
+	assert.Equal(t, "INSERT INTO [testing_table] ([testing_1]) OUTPUT INSERTED.* VALUES (@p1)", builder.ToQueryString())
+	assert.Equal(t, []any{"testing"}, builder.GetArgsValue())
+}
+
+func TestItCanGenerateUpdateCommandMssql(t *testing.T) {
+	builder := builder.NewMssqlBuilder(context.Background(), nil, "testing_update")
+	builder.Update(map[string]any{
+		"update_1": "update",
+		"update_2": 123.5,
+	}).Returning("id", "update_1").Where("id", 7)
+	assert.Equal(t, "UPDATE [testing_update] SET [update_1]=@p1,[update_2]=@p2 OUTPUT INSERTED.[id],INSERTED.[update_1] WHERE [id]=@p3", builder.ToQueryString())
+	assert.Equal(t, []any{"update", 123.5, 7}, builder.GetArgsValue())
+}
+
+func TestItCanGenerateWhereCommandMssql(t *testing.T) {
+	builder := builder.NewMssqlBuilder(context.Background(), nil, "testing_where")
+	builder.Select([]string{"id", "column_1 as col_1"}).
+		Where("conditional", "value").
+		WhereLike("like_pattern", `%value%`).
+		OrWhere("t.or_pattern", 4321)
+	assert.Equal(t, "SELECT [id],column_1 as col_1 FROM [testing_where] WHERE [conditional]=@p1 AND [like_pattern] LIKE @p2 OR [t].[or_pattern]=@p3", builder.ToQueryString())
+	assert.Equal(t, []any{"value", `%value%`, 4321}, builder.GetArgsValue())
+}
+
+func TestItCanGenerateTopQueryMssql(t *testing.T) {
+	builder := builder.NewMssqlBuilder(context.Background(), nil, "testing_top")
+	builder.Select([]string{"*"}).Where("active", true).OrderBy("id", "desc").Limit(10)
+	assert.Equal(t, "SELECT TOP (10) * FROM [testing_top] WHERE [active]=@p1 ORDER BY [id] desc", builder.ToQueryString())
+	assert.Equal(t, []any{true}, builder.GetArgsValue())
+}
+
+func TestItCanGenerateTopZeroQueryMssql(t *testing.T) {
+	builder := builder.NewMssqlBuilder(context.Background(), nil, "testing_top")
+	builder.Select([]string{"id"}).Limit(0)
+	assert.Equal(t, "SELECT TOP (0) [id] FROM [testing_top]", builder.ToQueryString())
+}
+
+func TestItCanNotLimitUpdateOrDeleteMssql(t *testing.T) {
+	update := builder.NewMssqlBuilder(context.Background(), nil, "testing_top")
+	update.Update(map[string]any{"active": false}).Where("id", 1).Limit(10)
+	assert.Contains(t, update.ToQueryString(), "builder: LIMIT and OFFSET are only supported on SELECT by mssql")
+
+	remove := builder.NewMssqlBuilder(context.Background(), nil, "testing_top")
+	remove.Delete().Where("id", 1).Offset(5)
+	assert.Contains(t, remove.ToQueryString(), "builder: LIMIT and OFFSET are only supported on SELECT by mssql")
+}
+
+func TestItCanGenerateOffsetFetchQueryMssql(t *testing.T) {
+	t.Run("Testing it can generate 'offset fetch' after order by", func(t *testing.T) {
+		builder := builder.NewMssqlBuilder(context.Background(), nil, "testing_fetch")
+		builder.Select([]string{"id"}).Limit(10).Offset(20).OrderBy("id", "asc")
+		assert.Equal(t, "SELECT [id] FROM [testing_fetch] ORDER BY [id] asc OFFSET 20 ROWS FETCH NEXT 10 ROWS ONLY", builder.ToQueryString())
+		assert.Empty(t, builder.GetArgsValue())
+	})
+	t.Run("Testing it can generate default order by when offset used without order by", func(t *testing.T) {
+		builder := builder.NewMssqlBuilder(context.Background(), nil, "testing_fetch")
+		builder.Select([]string{"id"}).Offset(5)
+		assert.Equal(t, "SELECT [id] FROM [testing_fetch] ORDER BY (SELECT NULL) OFFSET 5 ROWS", builder.ToQueryString())
+	})
+}
+
+func TestItCanGenerateJoinTableQueryMssql(t *testing.T) {
+	builder := builder.NewMssqlBuilder(context.Background(), nil, "testing_join")
+	builder.JoinTable("left", "first_table as ft", "ft.id=testing_join.id")
+	assert.Equal(t, " LEFT JOIN first_table as ft ON ft.id=testing_join.id", builder.ToQueryString())
+}
+
+func TestItCanGenerateMergeCommandMssql(t *testing.T) {
+	builder := builder.NewMssqlBuilder(context.Background(), nil, "testing_merge")
+	builder.Upsert(map[string]any{"id": 7, "name": "value"}, []string{"id"}).Returning()
+	assert.Equal(t, "MERGE INTO [testing_merge] AS target USING (SELECT @p1 AS [id],@p2 AS [name]) AS source ON (target.[id]=source.[id])"+
+		" WHEN MATCHED THEN UPDATE SET target.[name]=source.[name]"+
+		" WHEN NOT MATCHED THEN INSERT ([id],[name]) VALUES (source.[id],source.[name]) OUTPUT INSERTED.*;", builder.ToQueryString())
+	assert.Equal(t, []any{7, "value"}, builder.GetArgsValue())
+}
+
+func TestItCanNotChainWhereAfterMergeMssql(t *testing.T) {
+	builder := builder.NewMssqlBuilder(context.Background(), nil, "testing_merge")
+	builder.Upsert(map[string]any{"id": 7, "name": "value"}, []string{"id"}).Where("id", 7)
+	query := builder.ToQueryString()
+	assert.Contains(t, query, "builder: WHERE can not follow a MERGE upsert on mssql")
+	assert.NotContains(t, query, ";")
+}
+
+func TestItCanGenerateDeleteCommandMssql(t *testing.T) {
+	builder := builder.NewMssqlBuilder(context.Background(), nil, "testing_delete")
+	builder.Delete().Where("id", 1).Returning("id")
+	assert.Equal(t, "DELETE FROM [testing_delete] OUTPUT DELETED.[id] WHERE [id]=@p1", builder.ToQueryString())
+	assert.Equal(t, []any{1}, builder.GetArgsValue())
+}
@@ -2,6 +2,7 @@ package builder_test
 
 import (
 	"context"
+	"database/sql"
 	"strings"
 	"testing"
 	"time"
@@ -93,3 +94,28 @@ func TestItCanGenerateOrderByQueryMysql(t *testing.T) {
 	builder.OrderBy("order_col", "asc")
 	assert.Equal(t, " ORDER BY order_col asc", builder.ToQueryString())
 }
+
+func TestItCanGenerateLimitOffsetQueryMysql(t *testing.T) {
+	builder := builder.NewMysqlBuilder(context.Background(), nil, "testing_limit")
+	builder.Select([]string{"id"}).Where("active", true).OrderBy("id", "asc").Limit(10).Offset(20)
+	assert.Equal(t, "SELECT id FROM testing_limit WHERE active=? ORDER BY id asc LIMIT 10 OFFSET 20", builder.ToQueryString())
+	assert.Equal(t, []any{true}, builder.GetArgsValue())
+}
+
+func TestItCanGenerateUpsertCommandMysql(t *testing.T) {
+	builder := builder.NewMysqlBuilder(context.Background(), nil, "testing_upsert")
+	builder.Upsert(map[string]any{"id": 1, "name": "value"}, []string{"id"})
+	assert.Equal(t, "INSERT INTO testing_upsert (id,name) VALUES (?,?) ON DUPLICATE KEY UPDATE name=VALUES(name)", builder.ToQueryString())
+	assert.Equal(t, []any{1, "value"}, builder.GetArgsValue())
+}
+
+func TestItCanReturnErrorWithoutConnectionMysql(t *testing.T) {
+	var db *sql.DB
+	sb := builder.NewMysqlBuilder(context.Background(), db, "users").Select([]string{"id"})
+	var id int
+	assert.ErrorIs(t, sb.RowQuery().Scan(&id), builder.ErrNilConnection)
+	_, err := sb.RowsQuery()
+	assert.ErrorIs(t, err, builder.ErrNilConnection)
+	_, err = sb.Exec()
+	assert.ErrorIs(t, err, builder.ErrNilConnection)
+}
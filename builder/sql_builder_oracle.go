@@ -0,0 +1,48 @@
+package builder
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	constants "github.com/zhuan69/go-simple-sql-builder/constants"
+	observable "github.com/zhuan69/go-simple-sql-builder/observable"
+)
+
+func NewOracleBuilder(ctx context.Context, conn SqlQuerier, tableName string) SqlBuilder[*sql.Row, *sql.Rows] {
+	return newSqlBuilder(ctx, observable.ORACLE, conn, tableName)
+}
+
+// prepareOracleObserve rewrites LIMIT/OFFSET into the row limiting clause,
+// which Oracle only accepts at the end of a query.
+func (sb *sqlBuilder[RT, RWT]) prepareOracleObserve(observes []observable.SqlObserve) []observable.SqlObserve {
+	prepared := make([]observable.SqlObserve, 0, len(observes))
+	limit, offset := -1, -1
+	isSelect := false
+	for _, v := range observes {
+		switch v.GetCommand() {
+		case constants.SELECT_KEY:
+			isSelect = true
+		case constants.LIMIT_KEY:
+			limit = v.GetValues()[0].(int)
+			continue
+		case constants.OFFSET_KEY:
+			offset = v.GetValues()[0].(int)
+			continue
+		}
+		prepared = append(prepared, v)
+	}
+	if !isSelect && (limit >= 0 || offset >= 0) {
+		// the row limiting clause is not valid on UPDATE, DELETE or MERGE
+		return append(prepared, sb.errorObserve(fmt.Errorf("builder: LIMIT and OFFSET are only supported on SELECT by %s", sb.typeSql)))
+	}
+	if limit >= 0 || offset >= 0 {
+		so := observable.NewSqlObserve(constants.FETCH_KEY, sb.typeSql, sb.tableName, false, 0, 0)
+		so.SetValue(offset, limit)
+		prepared = append(prepared, so)
+	}
+	if err := sb.mergeClauseError(observes); err != nil {
+		prepared = append(prepared, sb.errorObserve(err))
+	}
+	return prepared
+}
@@ -0,0 +1,95 @@
+package builder_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	builder "github.com/zhuan69/go-simple-sql-builder/builder"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestItCanGenerateInsertCommandOracle(t *testing.T) {
+	builder := builder.NewOracleBuilder(context.Background(), nil, "testing_table")
+	builder.Insert(map[string]any{
+		"testing_1": "testing",
+		"testing_2": "testing_2",
+		"testing_3": 123,
+	})
+	assert.Equal(t, `INSERT INTO "testing_table" ("testing_1","testing_2","testing_3") VALUES (:1,:2,:3)`, builder.ToQueryString())
+	assert.Equal(t, []any{"testing", "testing_2", 123}, builder.GetArgsValue())
+}
+
+func TestItCanGenerateUpdateCommandOracle(t *testing.T) {
+	builder := builder.NewOracleBuilder(context.Background(), nil, "testing_update")
+	builder.Update(map[string]any{
+		"update_1": "update",
+		"update_2": 123.5,
+	}).Where("id", 7)
+	assert.Equal(t, `UPDATE "testing_update" SET "update_1"=:1,"update_2"=:2 WHERE "id"=:3`, builder.ToQueryString())
+	assert.Equal(t, []any{"update", 123.5, 7}, builder.GetArgsValue())
+}
+
+func TestItCanGenerateNamedBindVariablesOracle(t *testing.T) {
+	builder := builder.NewOracleBuilder(context.Background(), nil, "testing_named")
+	builder.Select([]string{"id", "name"}).
+		Where("id", sql.Named("id", 7)).
+		WhereLike("name", sql.Named("pattern", "%value%"))
+	assert.Equal(t, `SELECT "id","name" FROM "testing_named" WHERE "id"=:id AND "name" LIKE :pattern`, builder.ToQueryString())
+	assert.Equal(t, []any{sql.Named("id", 7), sql.Named("pattern", "%value%")}, builder.GetArgsValue())
+}
+
+func TestItCanGenerateFetchFirstQueryOracle(t *testing.T) {
+	t.Run("Testing it can generate 'fetch first' when only limit invoked", func(t *testing.T) {
+		builder := builder.NewOracleBuilder(context.Background(), nil, "testing_fetch")
+		builder.Select([]string{"id"}).Limit(10).OrderBy("id", "asc")
+		assert.Equal(t, `SELECT "id" FROM "testing_fetch" ORDER BY "id" asc FETCH FIRST 10 ROWS ONLY`, builder.ToQueryString())
+	})
+	t.Run("Testing it can generate 'offset fetch next' when offset invoked", func(t *testing.T) {
+		builder := builder.NewOracleBuilder(context.Background(), nil, "testing_fetch")
+		builder.Select([]string{"id"}).OrderBy("id", "asc").Offset(20).Limit(10)
+		assert.Equal(t, `SELECT "id" FROM "testing_fetch" ORDER BY "id" asc OFFSET 20 ROWS FETCH NEXT 10 ROWS ONLY`, builder.ToQueryString())
+	})
+}
+
+func TestItCanGenerateMergeCommandOracle(t *testing.T) {
+	builder := builder.NewOracleBuilder(context.Background(), nil, "testing_merge")
+	builder.Upsert(map[string]any{
+		"id":   7,
+		"name": "value",
+	}, []string{"id"})
+	assert.Equal(t, `MERGE INTO "testing_merge" target USING (SELECT :1 AS "id",:2 AS "name" FROM dual) source ON (target."id"=source."id")`+
+		` WHEN MATCHED THEN UPDATE SET target."name"=source."name"`+
+		` WHEN NOT MATCHED THEN INSERT ("id","name") VALUES (source."id",source."name")`, builder.ToQueryString())
+	assert.Equal(t, []any{7, "value"}, builder.GetArgsValue())
+}
+
+func TestItCanNotChainWhereOrReturningAfterMergeOracle(t *testing.T) {
+	where := builder.NewOracleBuilder(context.Background(), nil, "testing_merge")
+	where.Upsert(map[string]any{"id": 7, "name": "value"}, []string{"id"}).Where("name", "value")
+	assert.Contains(t, where.ToQueryString(), "builder: WHERE can not follow a MERGE upsert on oracle")
+
+	returning := builder.NewOracleBuilder(context.Background(), nil, "testing_merge")
+	returning.Upsert(map[string]any{"id": 7, "name": "value"}, []string{"id"}).Returning("id")
+	assert.Contains(t, returning.ToQueryString(), "builder: RETURNING can not follow a MERGE upsert on oracle")
+	assert.NotContains(t, returning.ToQueryString(), ";")
+}
+
+func TestItCanNotLimitWritesOracle(t *testing.T) {
+	update := builder.NewOracleBuilder(context.Background(), nil, "testing_fetch")
+	update.Update(map[string]any{"active": false}).Where("id", 1).Limit(10)
+	assert.Equal(t, `UPDATE "testing_fetch" SET "active"=:1 WHERE "id"=:2`+
+		`builder: LIMIT and OFFSET are only supported on SELECT by oracle`, update.ToQueryString())
+
+	remove := builder.NewOracleBuilder(context.Background(), nil, "testing_fetch")
+	remove.Delete().Where("id", 1).Offset(5)
+	assert.Equal(t, `DELETE FROM "testing_fetch" WHERE "id"=:1`+
+		`builder: LIMIT and OFFSET are only supported on SELECT by oracle`, remove.ToQueryString())
+
+	merge := builder.NewOracleBuilder(context.Background(), nil, "testing_fetch")
+	merge.Upsert(map[string]any{"id": 7}, []string{"id"}).Limit(1)
+	assert.Contains(t, merge.ToQueryString(), `MERGE INTO "testing_fetch" target`)
+	assert.NotContains(t, merge.ToQueryString(), "FETCH FIRST")
+	assert.Contains(t, merge.ToQueryString(), "builder: LIMIT and OFFSET are only supported on SELECT by oracle")
+}
@@ -7,7 +7,9 @@ import (
 	"time"
 
 	builder "github.com/zhuan69/go-simple-sql-builder/builder"
+	mocks "github.com/zhuan69/go-simple-sql-builder/tests/mocks/postgresql"
 
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -100,3 +102,83 @@ func TestItCanGenerateOrderByQueryPgsql(t *testing.T) {
 	builder.OrderBy("order_col", "asc")
 	assert.Equal(t, " ORDER BY order_col asc", builder.ToQueryString())
 }
+
+func TestItCanGenerateLimitOffsetQueryPgsql(t *testing.T) {
+	builder := builder.NewPgsqlBuilder(context.Background(), nil, "testing_limit")
+	builder.Select([]string{"id"}).Where("active", true).OrderBy("id", "asc").Limit(10).Offset(20)
+	assert.Equal(t, "SELECT id FROM testing_limit WHERE active=$1 ORDER BY id asc LIMIT 10 OFFSET 20", builder.ToQueryString())
+	assert.Equal(t, []any{true}, builder.GetArgsValue())
+}
+
+func TestItCanGenerateReturningQueryPgsql(t *testing.T) {
+	builder := builder.NewPgsqlBuilder(context.Background(), nil, "testing_returning")
+	builder.Insert(map[string]any{"name": "value"}).Returning("id")
+	assert.Equal(t, "INSERT INTO testing_returning (name) VALUES ($1) RETURNING id", builder.ToQueryString())
+}
+
+func TestItCanGenerateUpsertCommandPgsql(t *testing.T) {
+	t.Run("Testing it can generate 'on conflict do update'", func(t *testing.T) {
+		builder := builder.NewPgsqlBuilder(context.Background(), nil, "testing_upsert")
+		builder.Upsert(map[string]any{"id": 1, "name": "value"}, []string{"id"})
+		assert.Equal(t, "INSERT INTO testing_upsert (id,name) VALUES ($1,$2) ON CONFLICT (id) DO UPDATE SET name=EXCLUDED.name", builder.ToQueryString())
+		assert.Equal(t, []any{1, "value"}, builder.GetArgsValue())
+	})
+	t.Run("Testing it can generate 'on conflict do nothing' when every column is a conflict column", func(t *testing.T) {
+		builder := builder.NewPgsqlBuilder(context.Background(), nil, "testing_upsert")
+		builder.Upsert(map[string]any{"id": 1}, []string{"id"})
+		assert.Equal(t, "INSERT INTO testing_upsert (id) VALUES ($1) ON CONFLICT (id) DO NOTHING", builder.ToQueryString())
+	})
+}
+
+func TestItCanGenerateDeleteCommandPgsql(t *testing.T) {
+	builder := builder.NewPgsqlBuilder(context.Background(), nil, "testing_delete")
+	builder.Delete().Where("id", 1).Returning("id")
+	assert.Equal(t, "DELETE FROM testing_delete WHERE id=$1 RETURNING id", builder.ToQueryString())
+	assert.Equal(t, []any{1}, builder.GetArgsValue())
+}
+
+func TestItCanExecuteQueryPgsql(t *testing.T) {
+	ctx := context.Background()
+	t.Run("Testing it can query a row", func(t *testing.T) {
+		pool := &mocks.MockPgxPool{}
+		pool.On("QueryRow", ctx, "SELECT name FROM users WHERE id=$1", 1).
+			Return(mocks.MockRow{Rows: mocks.NewMockRows([]string{"name"}, []any{"zhuan"})})
+		var name string
+		err := builder.NewPgsqlBuilder(ctx, pool, "users").Select([]string{"name"}).Where("id", 1).RowQuery().Scan(&name)
+		assert.NoError(t, err)
+		assert.Equal(t, "zhuan", name)
+		pool.AssertExpectations(t)
+	})
+	t.Run("Testing it can query rows into structs", func(t *testing.T) {
+		pool := &mocks.MockPgxPool{}
+		rows := mocks.NewMockRows([]string{"id", "name"}, []any{int64(1), "zhuan"}, []any{int64(2), nil})
+		pool.On("Query", ctx, "SELECT id,name FROM users WHERE active=$1", true).Return(rows, nil)
+		type user struct {
+			ID   int64
+			Name *string
+		}
+		users, err := builder.Select[user](builder.NewPgsqlBuilder(ctx, pool, "users").Select([]string{"id", "name"}).Where("active", true))
+		assert.NoError(t, err)
+		assert.Len(t, users, 2)
+		assert.Equal(t, "zhuan", *users[0].Name)
+		assert.Nil(t, users[1].Name)
+		assert.True(t, rows.IsClosed())
+		pool.AssertExpectations(t)
+	})
+	t.Run("Testing it can exec a command", func(t *testing.T) {
+		pool := &mocks.MockPgxPool{}
+		pool.On("Exec", ctx, "DELETE FROM users WHERE id=$1", 1).Return(pgconn.NewCommandTag("DELETE 1"), nil)
+		affected, err := builder.NewPgsqlBuilder(ctx, pool, "users").Delete().Where("id", 1).Exec()
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), affected)
+		pool.AssertExpectations(t)
+	})
+	t.Run("Testing it return error without connection", func(t *testing.T) {
+		sb := builder.NewPgsqlBuilder(ctx, nil, "users").Select([]string{"id"})
+		assert.ErrorIs(t, sb.RowQuery().Scan(), builder.ErrNilConnection)
+		_, err := sb.RowsQuery()
+		assert.ErrorIs(t, err, builder.ErrNilConnection)
+		_, err = sb.Exec()
+		assert.ErrorIs(t, err, builder.ErrNilConnection)
+	})
+}
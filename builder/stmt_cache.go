@@ -0,0 +1,207 @@
+package builder
+
+import (
+	"container/list"
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"sync"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// DefaultStmtCacheSize is used by NewStmtCache when size is not positive.
+const DefaultStmtCacheSize = 128
+
+// StmtPreparer is satisfied by *sql.DB, *sql.Conn and *sql.Tx.
+type StmtPreparer interface {
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+}
+
+// StmtCache prepares every query once and reuses the statement while it
+// stays among the size most recently used ones, the least recently used
+// being closed. It satisfies SqlQuerier and SqlExecer so it is given to the
+// builders in place of the *sql.DB it wraps.
+type StmtCache struct {
+	db    StmtPreparer
+	size  int
+	mu    sync.Mutex
+	order *list.List
+	stmts map[string]*list.Element
+}
+
+type cachedStmt struct {
+	query string
+	stmt  *sql.Stmt
+}
+
+var _ SqlExecutor = (*StmtCache)(nil)
+
+func NewStmtCache(db StmtPreparer, size int) *StmtCache {
+	if size <= 0 {
+		size = DefaultStmtCacheSize
+	}
+	return &StmtCache{db: db, size: size, order: list.New(), stmts: map[string]*list.Element{}}
+}
+
+// IsStmtInvalidated reports whether err means the prepared statement can not
+// be used anymore, such as after a schema change, and has to be prepared
+// again. Inside a PostgreSQL transaction the failed statement aborts the
+// transaction, so a *StmtCache over a *sql.Tx returns that error as is.
+func IsStmtInvalidated(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, sql.ErrConnDone) {
+		return false
+	}
+	msg := err.Error()
+	return strings.Contains(msg, "cached plan must not change result type") ||
+		strings.Contains(msg, "Error 1615") ||
+		strings.Contains(msg, "needs to be re-prepared") ||
+		strings.Contains(msg, "statement is closed")
+}
+
+func (c *StmtCache) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	stmt, err := c.prepare(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := stmt.QueryContext(ctx, args...)
+	if c.canReprepare(err) {
+		if stmt, err = c.reprepare(ctx, query, stmt); err != nil {
+			return nil, err
+		}
+		return stmt.QueryContext(ctx, args...)
+	}
+	return rows, err
+}
+
+func (c *StmtCache) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	stmt, err := c.prepare(ctx, query)
+	if err != nil {
+		// never run the query unprepared, a failed prepare such as
+		// max_prepared_stmt_count must not still apply a write
+		return errSqlRow(ctx, err)
+	}
+	row := stmt.QueryRowContext(ctx, args...)
+	if c.canReprepare(row.Err()) {
+		if stmt, err = c.reprepare(ctx, query, stmt); err == nil {
+			return stmt.QueryRowContext(ctx, args...)
+		}
+	}
+	return row
+}
+
+func (c *StmtCache) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	stmt, err := c.prepare(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	res, err := stmt.ExecContext(ctx, args...)
+	if c.canReprepare(err) {
+		if stmt, err = c.reprepare(ctx, query, stmt); err != nil {
+			return nil, err
+		}
+		return stmt.ExecContext(ctx, args...)
+	}
+	return res, err
+}
+
+// canReprepare reports whether the statement is prepared again and run once
+// more after err, which PostgreSQL can not do in the transaction it aborted.
+func (c *StmtCache) canReprepare(err error) bool {
+	if !IsStmtInvalidated(err) {
+		return false
+	}
+	_, inTx := c.db.(*sql.Tx)
+	return !inTx || !strings.Contains(err.Error(), "cached plan must not change result type")
+}
+
+// Len returns the number of cached statements.
+func (c *StmtCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// Close closes every cached statement, the cache can still be used after.
+func (c *StmtCache) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var errs []error
+	for _, v := range c.stmts {
+		errs = append(errs, v.Value.(*cachedStmt).stmt.Close())
+	}
+	c.order.Init()
+	c.stmts = map[string]*list.Element{}
+	return errors.Join(errs...)
+}
+
+func (c *StmtCache) prepare(ctx context.Context, query string) (*sql.Stmt, error) {
+	c.mu.Lock()
+	if el, ok := c.stmts[query]; ok {
+		c.order.MoveToFront(el)
+		c.mu.Unlock()
+		return el.Value.(*cachedStmt).stmt, nil
+	}
+	c.mu.Unlock()
+	// preparing without the lock, two goroutines may prepare the same query
+	// and the last one is kept
+	stmt, err := c.db.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.stmts[query]; ok {
+		stmt.Close()
+		c.order.MoveToFront(el)
+		return el.Value.(*cachedStmt).stmt, nil
+	}
+	c.stmts[query] = c.order.PushFront(&cachedStmt{query: query, stmt: stmt})
+	for c.order.Len() > c.size {
+		c.evict(c.order.Back())
+	}
+	return stmt, nil
+}
+
+// reprepare drops the invalidated statement, unless another goroutine
+// already replaced it, and prepares the query again.
+func (c *StmtCache) reprepare(ctx context.Context, query string, stale *sql.Stmt) (*sql.Stmt, error) {
+	c.mu.Lock()
+	if el, ok := c.stmts[query]; ok && el.Value.(*cachedStmt).stmt == stale {
+		c.evict(el)
+	}
+	c.mu.Unlock()
+	return c.prepare(ctx, query)
+}
+
+func (c *StmtCache) evict(el *list.Element) {
+	cached := c.order.Remove(el).(*cachedStmt)
+	delete(c.stmts, cached.query)
+	// database/sql defers the close until running queries are done
+	cached.stmt.Close()
+}
+
+// ConfigurePgxStatementCache sets how pgx caches the statements of a
+// connection config, pgxpool.Config.ConnConfig included. pgx keeps its own
+// LRU keyed by SQL and invalidates it on schema changes.
+// QueryExecModeCacheStatement caches prepared statements,
+// QueryExecModeCacheDescribe only their descriptions, and the other modes
+// disable both caches.
+func ConfigurePgxStatementCache(cfg *pgx.ConnConfig, mode pgx.QueryExecMode, size int) {
+	if size <= 0 {
+		size = DefaultStmtCacheSize
+	}
+	cfg.DefaultQueryExecMode = mode
+	cfg.StatementCacheCapacity = 0
+	cfg.DescriptionCacheCapacity = 0
+	switch mode {
+	case pgx.QueryExecModeCacheStatement:
+		cfg.StatementCacheCapacity = size
+	case pgx.QueryExecModeCacheDescribe:
+		cfg.DescriptionCacheCapacity = size
+	}
+}
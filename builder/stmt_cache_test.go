@@ -0,0 +1,119 @@
+package builder_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	builder "github.com/zhuan69/go-simple-sql-builder/builder"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestItCanReusePreparedStatements(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer db.Close()
+	prep := mock.ExpectPrepare("SELECT id FROM users WHERE id=?")
+	prep.ExpectQuery().WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	prep.ExpectQuery().WithArgs(2).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+
+	cache := builder.NewStmtCache(db, 10)
+	for _, id := range []int64{1, 2} {
+		got, err := builder.Get[int64](builder.NewMysqlBuilder(context.Background(), cache, "users").
+			Select([]string{"id"}).Where("id", id))
+		require.NoError(t, err)
+		assert.Equal(t, id, got)
+	}
+	assert.Equal(t, 1, cache.Len())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestItCanEvictLeastRecentlyUsedStatement(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer db.Close()
+	mock.ExpectPrepare("DELETE FROM users WHERE id=?").WillBeClosed().
+		ExpectExec().WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectPrepare("DELETE FROM orders WHERE id=?").
+		ExpectExec().WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectPrepare("DELETE FROM users WHERE id=?").
+		ExpectExec().WithArgs(2).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	cache := builder.NewStmtCache(db, 1)
+	ctx := context.Background()
+	for _, v := range []struct {
+		table string
+		id    int
+	}{{"users", 1}, {"orders", 1}, {"users", 2}} {
+		_, err := builder.NewMysqlBuilder(ctx, cache, v.table).Delete().Where("id", v.id).Exec()
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 1, cache.Len())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestItCanReprepareInvalidatedStatement(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer db.Close()
+	mock.ExpectPrepare("SELECT * FROM users").WillBeClosed().
+		ExpectQuery().WillReturnError(errors.New("ERROR: cached plan must not change result type (SQLSTATE 0A000)"))
+	mock.ExpectPrepare("SELECT * FROM users").
+		ExpectQuery().WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+	cache := builder.NewStmtCache(db, 0)
+	rows, err := builder.NewMysqlBuilder(context.Background(), cache, "users").Select([]string{"*"}).RowsQuery()
+	require.NoError(t, err)
+	require.NoError(t, rows.Close())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestItCanConfigurePgxStatementCache(t *testing.T) {
+	cfg, err := pgx.ParseConfig("postgres://localhost/db")
+	require.NoError(t, err)
+	builder.ConfigurePgxStatementCache(cfg, pgx.QueryExecModeCacheDescribe, 64)
+	assert.Equal(t, pgx.QueryExecModeCacheDescribe, cfg.DefaultQueryExecMode)
+	assert.Equal(t, 0, cfg.StatementCacheCapacity)
+	assert.Equal(t, 64, cfg.DescriptionCacheCapacity)
+
+	builder.ConfigurePgxStatementCache(cfg, pgx.QueryExecModeExec, 64)
+	assert.Equal(t, 0, cfg.DescriptionCacheCapacity)
+	assert.True(t, builder.IsStmtInvalidated(errors.New("Error 1615 (HY000): Prepared statement needs to be re-prepared")))
+}
+
+func TestItCanReturnPrepareErrorWithoutRunningQuery(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer db.Close()
+	prepareErr := errors.New("Error 1461 (42000): Can't create more than max_prepared_stmt_count statements")
+	mock.ExpectPrepare("SELECT id FROM users WHERE id=?").WillReturnError(prepareErr)
+
+	cache := builder.NewStmtCache(db, 10)
+	_, err = builder.Get[int64](builder.NewMysqlBuilder(context.Background(), cache, "users").
+		Select([]string{"id"}).Where("id", 1))
+	assert.ErrorContains(t, err, "max_prepared_stmt_count")
+	assert.Equal(t, 0, cache.Len())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestItCanNotReprepareInsideAbortedPgsqlTransaction(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer db.Close()
+	mock.ExpectBegin()
+	mock.ExpectPrepare("SELECT * FROM users").
+		ExpectQuery().WillReturnError(errors.New("ERROR: cached plan must not change result type (SQLSTATE 0A000)"))
+	mock.ExpectRollback()
+
+	tx, err := db.Begin()
+	require.NoError(t, err)
+	cache := builder.NewStmtCache(tx, 0)
+	_, err = builder.NewMysqlBuilder(context.Background(), cache, "users").Select([]string{"*"}).RowsQuery()
+	assert.ErrorContains(t, err, "cached plan must not change result type")
+	require.NoError(t, tx.Rollback())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,111 @@
+package builder
+
+import (
+	"fmt"
+	"reflect"
+
+	constants "github.com/zhuan69/go-simple-sql-builder/constants"
+	observable "github.com/zhuan69/go-simple-sql-builder/observable"
+)
+
+// StructOptions controls which struct fields UpdateStruct writes. Fields
+// tagged `db:"...,omitempty"` are always skipped when zero-valued, SkipZero
+// extends that to every field. A struct without a `pk` field needs a Where
+// on the builder, unless UpdateAll opts in to updating every row.
+type StructOptions struct {
+	SkipZero  bool
+	UpdateAll bool
+}
+
+type structColumns struct {
+	columns   []string
+	values    []any
+	pkColumns []string
+	pkValues  []any
+}
+
+// readStructColumns reads the columns and values of v in field order,
+// leaving out `readonly` fields and zero-valued `omitempty` fields. Fields
+// tagged `pk` are returned apart when excludePk is set.
+func readStructColumns(v any, opts StructOptions, excludePk bool) (structColumns, error) {
+	var sc structColumns
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return sc, fmt.Errorf("EXPECTED STRUCT BUT GOT:%T", v)
+	}
+	for _, f := range getStructMap(rv.Type()).fields {
+		if f.options["readonly"] {
+			continue
+		}
+		fv, ok := fieldValue(rv, f.index)
+		if !ok {
+			continue
+		}
+		if excludePk && f.options["pk"] {
+			sc.pkColumns = append(sc.pkColumns, f.column)
+			sc.pkValues = append(sc.pkValues, fv.Interface())
+			continue
+		}
+		if fv.IsZero() && (opts.SkipZero || f.options["omitempty"]) {
+			continue
+		}
+		sc.columns = append(sc.columns, f.column)
+		sc.values = append(sc.values, fv.Interface())
+	}
+	return sc, nil
+}
+
+func (sb *sqlBuilder[RT, RWT]) insertStruct(v any) *sqlBuilder[RT, RWT] {
+	sc, err := readStructColumns(v, StructOptions{}, false)
+	if err != nil {
+		return sb.registerError(err)
+	}
+	sb.registerObserve(sb.colsAndValsObserve(constants.INSERT_KEY, sc.columns, sc.values))
+	return sb
+}
+
+func (sb *sqlBuilder[RT, RWT]) updateStruct(v any, opts StructOptions) *sqlBuilder[RT, RWT] {
+	sc, err := readStructColumns(v, opts, true)
+	if err != nil {
+		return sb.registerError(err)
+	}
+	if len(sc.columns) == 0 {
+		return sb.registerError(fmt.Errorf("builder: UpdateStruct has no column to update"))
+	}
+	sb.registerObserve(sb.colsAndValsObserve(constants.UPDATE_KEY, sc.columns, sc.values))
+	for i, col := range sc.pkColumns {
+		sb.where(col, sc.pkValues[i])
+	}
+	// without a pk the Where has to come from the caller, checked on render
+	sb.requireWhere = len(sc.pkColumns) == 0 && !opts.UpdateAll
+	return sb
+}
+
+// whereError reports an UpdateStruct left without any condition, which
+// would update every row of the table.
+func (sb *sqlBuilder[RT, RWT]) whereError(observes []observable.SqlObserve) error {
+	if !sb.requireWhere {
+		return nil
+	}
+	for _, v := range observes {
+		switch v.GetCommand() {
+		case constants.WHERE_KEY, constants.WHERE_LIKE_KEY, constants.SEEK_KEY:
+			return nil
+		}
+	}
+	return fmt.Errorf("builder: UpdateStruct without a pk field needs Where or StructOptions.UpdateAll")
+}
+
+func (sb *sqlBuilder[RT, RWT]) registerError(err error) *sqlBuilder[RT, RWT] {
+	sb.registerObserve(sb.errorObserve(err))
+	return sb
+}
+
+func (sb *sqlBuilder[RT, RWT]) errorObserve(err error) observable.SqlObserve {
+	so := observable.NewSqlObserve(constants.ERROR_KEY, sb.typeSql, sb.tableName, false, 0, 0)
+	so.SetValue(err.Error())
+	return so
+}
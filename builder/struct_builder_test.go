@@ -0,0 +1,81 @@
+package builder_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	builder "github.com/zhuan69/go-simple-sql-builder/builder"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type audit struct {
+	CreatedBy string `db:"created_by"`
+}
+
+type structProduct struct {
+	ID        int64     `db:"id,pk,omitempty"`
+	Name      string    `db:"name"`
+	Price     float64   `db:"price"`
+	Stock     int       `db:"stock,omitempty"`
+	Note      *string   `db:"note"`
+	CreatedAt time.Time `db:"created_at,readonly"`
+	*audit
+}
+
+func TestItCanGenerateInsertStructCommand(t *testing.T) {
+	t.Run("Testing it can generate insert in field order", func(t *testing.T) {
+		builder := builder.NewPgsqlBuilder(context.Background(), nil, "products")
+		builder.InsertStruct(&structProduct{Name: "book", Price: 10.5, audit: &audit{CreatedBy: "zhuan"}})
+		assert.Equal(t, "INSERT INTO products (name,price,note,created_by) VALUES ($1,$2,$3,$4)", builder.ToQueryString())
+		assert.Equal(t, []any{"book", 10.5, (*string)(nil), "zhuan"}, builder.GetArgsValue())
+	})
+	t.Run("Testing it can generate insert with primary key", func(t *testing.T) {
+		builder := builder.NewMysqlBuilder(context.Background(), nil, "products")
+		builder.InsertStruct(structProduct{ID: 3, Name: "pen", Stock: 2})
+		assert.Equal(t, "INSERT INTO products (id,name,price,stock,note) VALUES (?,?,?,?,?)", builder.ToQueryString())
+		assert.Equal(t, []any{int64(3), "pen", 0.0, 2, (*string)(nil)}, builder.GetArgsValue())
+	})
+	t.Run("Testing it can not generate insert from non struct", func(t *testing.T) {
+		builder := builder.NewMysqlBuilder(context.Background(), nil, "products")
+		builder.InsertStruct(map[string]any{"id": 1})
+		assert.Equal(t, "EXPECTED STRUCT BUT GOT:map[string]interface {}", builder.ToQueryString())
+	})
+}
+
+func TestItCanGenerateUpdateStructCommand(t *testing.T) {
+	t.Run("Testing it can generate update with primary key as conditional", func(t *testing.T) {
+		sb := builder.NewPgsqlBuilder(context.Background(), nil, "products")
+		sb.UpdateStruct(structProduct{ID: 3, Name: "pen"}, builder.StructOptions{})
+		assert.Equal(t, "UPDATE products SET name=$1,price=$2,note=$3 WHERE id=$4", sb.ToQueryString())
+		assert.Equal(t, []any{"pen", 0.0, (*string)(nil), int64(3)}, sb.GetArgsValue())
+	})
+	t.Run("Testing it can skip zero valued fields", func(t *testing.T) {
+		sb := builder.NewPgsqlBuilder(context.Background(), nil, "products")
+		sb.UpdateStruct(&structProduct{ID: 3, Price: 12}, builder.StructOptions{SkipZero: true})
+		assert.Equal(t, "UPDATE products SET price=$1 WHERE id=$2", sb.ToQueryString())
+		assert.Equal(t, []any{12.0, int64(3)}, sb.GetArgsValue())
+	})
+	t.Run("Testing it can not update every row without a primary key", func(t *testing.T) {
+		type product struct {
+			Name string `db:"name"`
+		}
+		sb := builder.NewMysqlBuilder(context.Background(), nil, "products")
+		sb.UpdateStruct(product{Name: "pen"}, builder.StructOptions{})
+		assert.Contains(t, sb.ToQueryString(), "builder: UpdateStruct without a pk field needs Where or StructOptions.UpdateAll")
+
+		sb = builder.NewMysqlBuilder(context.Background(), nil, "products")
+		sb.UpdateStruct(product{Name: "pen"}, builder.StructOptions{}).Where("sku", "p-1")
+		assert.Equal(t, "UPDATE products SET name=? WHERE sku=?", sb.ToQueryString())
+
+		sb = builder.NewMysqlBuilder(context.Background(), nil, "products")
+		sb.UpdateStruct(product{Name: "pen"}, builder.StructOptions{UpdateAll: true})
+		assert.Equal(t, "UPDATE products SET name=?", sb.ToQueryString())
+	})
+	t.Run("Testing it can not update without any column", func(t *testing.T) {
+		sb := builder.NewPgsqlBuilder(context.Background(), nil, "products")
+		sb.UpdateStruct(structProduct{ID: 3}, builder.StructOptions{SkipZero: true})
+		assert.Contains(t, sb.ToQueryString(), "builder: UpdateStruct has no column to update")
+	})
+}
@@ -0,0 +1,146 @@
+package builder
+
+import (
+	"database/sql"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// structField describes a struct field mapped to a column through its
+// `db:"name,option..."` tag, falling back to the snake_case field name.
+type structField struct {
+	column  string
+	index   []int
+	options map[string]bool
+}
+
+type structMap struct {
+	fields   []structField
+	byColumn map[string]structField
+}
+
+var structMapCache sync.Map
+
+var (
+	scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+	timeType    = reflect.TypeOf(time.Time{})
+)
+
+func getStructMap(t reflect.Type) *structMap {
+	if sm, ok := structMapCache.Load(t); ok {
+		return sm.(*structMap)
+	}
+	sm := &structMap{byColumn: map[string]structField{}}
+	collectStructFields(t, nil, sm)
+	structMapCache.Store(t, sm)
+	return sm
+}
+
+func collectStructFields(t reflect.Type, parent []int, sm *structMap) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag, hasTag := f.Tag.Lookup("db")
+		if tag == "-" {
+			continue
+		}
+		index := append(append([]int{}, parent...), i)
+		name, options := parseStructTag(tag)
+		if f.Anonymous && !hasTag && isNestedStruct(f.Type) {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			collectStructFields(ft, index, sm)
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = toSnakeCase(f.Name)
+		}
+		if _, exists := sm.byColumn[name]; exists {
+			continue
+		}
+		field := structField{column: name, index: index, options: options}
+		sm.fields = append(sm.fields, field)
+		sm.byColumn[name] = field
+	}
+}
+
+func parseStructTag(tag string) (string, map[string]bool) {
+	parts := strings.Split(tag, ",")
+	options := make(map[string]bool, len(parts)-1)
+	for _, v := range parts[1:] {
+		options[strings.TrimSpace(v)] = true
+	}
+	return strings.TrimSpace(parts[0]), options
+}
+
+// isNestedStruct reports whether t should be flattened into its parent,
+// which excludes types that are scanned as a single column.
+func isNestedStruct(t reflect.Type) bool {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct && !isScalarType(t)
+}
+
+func isScalarType(t reflect.Type) bool {
+	if t == timeType || t.Implements(scannerType) || reflect.PointerTo(t).Implements(scannerType) {
+		return true
+	}
+	if t.Kind() == reflect.Pointer {
+		return isScalarType(t.Elem())
+	}
+	return t.Kind() != reflect.Struct
+}
+
+// fieldByIndex walks the index path allocating nil embedded pointers so the
+// returned field is always addressable.
+func fieldByIndex(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v
+}
+
+// fieldValue walks the index path without allocating, reporting false when
+// an embedded pointer on the way is nil.
+func fieldValue(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
+
+func toSnakeCase(name string) string {
+	runes := []rune(name)
+	var snake strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && (unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1]))) {
+				snake.WriteRune('_')
+			}
+			snake.WriteRune(unicode.ToLower(r))
+			continue
+		}
+		snake.WriteRune(r)
+	}
+	return snake.String()
+}
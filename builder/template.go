@@ -0,0 +1,140 @@
+package builder
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// TemplateParam is a slot of a Template, filled by name when the template
+// is executed. It is created with Param.
+type TemplateParam struct {
+	Name string
+}
+
+// Param marks a value as a named slot, such as Where("id", Param("id")),
+// filled from the parameter map given to the Template compiled from the
+// builder.
+func Param(name string) TemplateParam {
+	return TemplateParam{Name: name}
+}
+
+// Template is a query compiled from a builder. Its SQL is rendered once and
+// it is never modified, so it can be executed from many goroutines with
+// different parameters.
+type Template[RT any, RWT any] struct {
+	sb     *sqlBuilder[RT, RWT]
+	query  string
+	args   []any
+	params []string
+}
+
+// Compile implements SqlBuilder
+func (sb *sqlBuilder[RT, RWT]) Compile() *Template[RT, RWT] {
+	frozen := sb.withObserve(sb.SqlObserve)
+	t := &Template[RT, RWT]{sb: frozen, query: frozen.sqlQueryString(), args: frozen.argsValue()}
+	seen := map[string]bool{}
+	for _, v := range t.args {
+		walkTemplateParams(v, func(p TemplateParam) {
+			if !seen[p.Name] {
+				seen[p.Name] = true
+				t.params = append(t.params, p.Name)
+			}
+		})
+	}
+	sort.Strings(t.params)
+	return t
+}
+
+// Query returns the compiled SQL.
+func (t *Template[RT, RWT]) Query() string {
+	return t.query
+}
+
+// Params returns the sorted names of the template slots.
+func (t *Template[RT, RWT]) Params() []string {
+	return append([]string{}, t.params...)
+}
+
+// Args returns the arguments of the query with every slot filled from
+// params, failing when one of them is missing.
+func (t *Template[RT, RWT]) Args(params map[string]any) ([]any, error) {
+	for _, v := range t.params {
+		if _, ok := params[v]; !ok {
+			return nil, fmt.Errorf("builder: missing template param %q", v)
+		}
+	}
+	args := make([]any, len(t.args))
+	for i, v := range t.args {
+		args[i] = fillTemplateParam(v, params)
+	}
+	return args, nil
+}
+
+func (t *Template[RT, RWT]) RowQuery(ctx context.Context, params map[string]any) (RT, error) {
+	var row RT
+	args, err := t.Args(params)
+	if err != nil {
+		return row, err
+	}
+	ctx, _, after := t.sb.beforeHooks(ctx, t.query, args)
+	return hookRow(t.sb.execRowCommand(ctx, t.query, args), after), nil
+}
+
+func (t *Template[RT, RWT]) RowsQuery(ctx context.Context, params map[string]any) (RWT, error) {
+	var rows RWT
+	args, err := t.Args(params)
+	if err != nil {
+		return rows, err
+	}
+	ctx, _, after := t.sb.beforeHooks(ctx, t.query, args)
+	rows, err = t.sb.execRowsCommand(ctx, t.query, args)
+	after(err)
+	return rows, err
+}
+
+func (t *Template[RT, RWT]) Exec(ctx context.Context, params map[string]any) (int64, error) {
+	args, err := t.Args(params)
+	if err != nil {
+		return 0, err
+	}
+	ctx, info, after := t.sb.beforeHooks(ctx, t.query, args)
+	n, err := t.sb.execCommand(ctx, t.query, args)
+	info.RowsAffected = n
+	after(err)
+	return n, err
+}
+
+// walkTemplateParams calls fn for the slots of an argument, including the
+// ones wrapped in named arguments.
+func walkTemplateParams(v any, fn func(p TemplateParam)) {
+	switch arg := v.(type) {
+	case TemplateParam:
+		fn(arg)
+	case sql.NamedArg:
+		walkTemplateParams(arg.Value, fn)
+	case pgx.NamedArgs:
+		for _, val := range arg {
+			walkTemplateParams(val, fn)
+		}
+	}
+}
+
+func fillTemplateParam(v any, params map[string]any) any {
+	switch arg := v.(type) {
+	case TemplateParam:
+		return params[arg.Name]
+	case sql.NamedArg:
+		return sql.Named(arg.Name, fillTemplateParam(arg.Value, params))
+	case pgx.NamedArgs:
+		named := make(pgx.NamedArgs, len(arg))
+		for k, val := range arg {
+			named[k] = fillTemplateParam(val, params)
+		}
+		return named
+	}
+	return v
+}
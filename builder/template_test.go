@@ -0,0 +1,76 @@
+package builder_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	builder "github.com/zhuan69/go-simple-sql-builder/builder"
+	observable "github.com/zhuan69/go-simple-sql-builder/observable"
+	fakedb "github.com/zhuan69/go-simple-sql-builder/tests/mocks/fakedb"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestItCanCompileTemplateWithParams(t *testing.T) {
+	tpl := builder.NewPgsqlBuilder(context.Background(), nil, "users").
+		Select([]string{"id"}).Where("active", true).Where("id", builder.Param("id")).Compile()
+	assert.Equal(t, "SELECT id FROM users WHERE active=$1 AND id=$2", tpl.Query())
+	assert.Equal(t, []string{"id"}, tpl.Params())
+
+	args, err := tpl.Args(map[string]any{"id": 7})
+	require.NoError(t, err)
+	assert.Equal(t, []any{true, 7}, args)
+
+	_, err = tpl.Args(map[string]any{"name": "zhuan"})
+	assert.EqualError(t, err, `builder: missing template param "id"`)
+}
+
+func TestItCanFillNamedTemplateParams(t *testing.T) {
+	tpl := builder.NewPgsqlBuilder(context.Background(), nil, "users").
+		Update(map[string]any{"name": builder.Param("name")}).Where("id", builder.Param("id")).
+		NamedParameters(observable.NAMED_AT).Compile()
+	assert.Equal(t, "UPDATE users SET name=@name WHERE id=@id", tpl.Query())
+	args, err := tpl.Args(map[string]any{"id": 1, "name": "zhuan"})
+	require.NoError(t, err)
+	assert.Equal(t, []any{pgx.NamedArgs{"id": 1, "name": "zhuan"}}, args)
+}
+
+func TestItCanExecuteTemplateConcurrently(t *testing.T) {
+	fake := fakedb.New(t, observable.MYSQL)
+	for i := 0; i < 8; i++ {
+		fake.ExpectQuery("UPDATE users SET seen=? WHERE id=?").WithArgs(true, i).WillReturnResult(1)
+	}
+	base := builder.NewMysqlBuilder(context.Background(), fake.DB(), "users").
+		Update(map[string]any{"seen": true}).Where("id", builder.Param("id"))
+	tpl := base.Compile()
+	base.Where("name", "changed after compile")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			n, err := tpl.Exec(context.Background(), map[string]any{"id": i})
+			assert.NoError(t, err)
+			assert.Equal(t, int64(1), n)
+		}(i)
+	}
+	wg.Wait()
+	assert.Equal(t, "UPDATE users SET seen=? WHERE id=?", tpl.Query())
+}
+
+func TestItCanQueryTemplateRows(t *testing.T) {
+	fake := fakedb.New(t, observable.MYSQL)
+	fake.ExpectQuery("SELECT id FROM users WHERE name=?").WithArgs("zhuan").WillReturnRows([]string{"id"}, []any{int64(3)})
+	tpl := builder.NewMysqlBuilder(context.Background(), fake.DB(), "users").
+		Select([]string{"id"}).Where("name", builder.Param("name")).Compile()
+
+	row, err := tpl.RowQuery(context.Background(), map[string]any{"name": "zhuan"})
+	require.NoError(t, err)
+	var id int64
+	require.NoError(t, row.Scan(&id))
+	assert.Equal(t, int64(3), id)
+}
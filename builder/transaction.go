@@ -0,0 +1,159 @@
+package builder
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	observable "github.com/zhuan69/go-simple-sql-builder/observable"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// TxOptions configures WithTx and WithPgxTx. Retries only apply to the
+// outermost transaction, nested calls run in a savepoint of their parent.
+// Dialect picks the savepoint statements of nested WithTx calls, the
+// standard SAVEPOINT syntax of MySQL and PostgreSQL when left empty.
+type TxOptions struct {
+	SqlTxOptions *sql.TxOptions
+	PgxTxOptions pgx.TxOptions
+	MaxRetries   int
+	IsRetryable  func(err error) bool
+	Dialect      observable.SqlType
+}
+
+var savepointCounter uint64
+
+// WithTx runs fn inside a transaction of db, committing when fn returns nil
+// and rolling back otherwise. When db is already a *sql.Tx the call is
+// nested and runs in a SAVEPOINT that is released or rolled back instead.
+func WithTx(ctx context.Context, db SqlExecutor, fn func(tx *sql.Tx) error, opts ...TxOptions) error {
+	opt := txOptions(opts)
+	if tx, ok := db.(*sql.Tx); ok {
+		return withSavepoint(ctx, tx, opt.Dialect, fn)
+	}
+	beginner, ok := db.(sqlTxBeginner)
+	if !ok {
+		return fmt.Errorf("builder: %T can not begin a transaction", db)
+	}
+	return retryTx(ctx, opt, func() error {
+		tx, err := beginner.BeginTx(ctx, opt.SqlTxOptions)
+		if err != nil {
+			return err
+		}
+		return finishTx(fn, tx, tx.Commit, tx.Rollback)
+	})
+}
+
+// WithPgxTx is the pgx counterpart of WithTx. When db is already a pgx.Tx
+// the call is nested and pgx runs it in a savepoint.
+func WithPgxTx(ctx context.Context, db PgxTxBeginner, fn func(tx pgx.Tx) error, opts ...TxOptions) error {
+	opt := txOptions(opts)
+	begin := func() (pgx.Tx, error) {
+		if beginner, ok := db.(pgxTxOptionsBeginner); ok {
+			return beginner.BeginTx(ctx, opt.PgxTxOptions)
+		}
+		return db.Begin(ctx)
+	}
+	if _, ok := db.(pgx.Tx); ok {
+		opt.MaxRetries = 0
+	}
+	return retryTx(ctx, opt, func() error {
+		tx, err := begin()
+		if err != nil {
+			return err
+		}
+		return finishTx(fn, tx, func() error {
+			return tx.Commit(ctx)
+		}, func() error {
+			return tx.Rollback(ctx)
+		})
+	})
+}
+
+// IsSerializationFailure reports whether err is a serialization failure or
+// deadlock that can be solved by running the transaction again.
+func IsSerializationFailure(err error) bool {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == "40001" || pgErr.Code == "40P01"
+	}
+	if err == nil {
+		return false
+	}
+	msg := err.Error()
+	return strings.Contains(msg, "Error 1213") || strings.Contains(msg, "(40001)")
+}
+
+func txOptions(opts []TxOptions) TxOptions {
+	if len(opts) == 0 {
+		return TxOptions{}
+	}
+	opt := opts[0]
+	if opt.IsRetryable == nil {
+		opt.IsRetryable = IsSerializationFailure
+	}
+	return opt
+}
+
+func retryTx(ctx context.Context, opt TxOptions, run func() error) error {
+	err := run()
+	for i := 0; i < opt.MaxRetries && err != nil && opt.IsRetryable(err); i++ {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return errors.Join(err, ctxErr)
+		}
+		err = run()
+	}
+	return err
+}
+
+func finishTx[TX any](fn func(tx TX) error, tx TX, commit func() error, rollback func() error) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			_ = rollback()
+			panic(p)
+		}
+	}()
+	if err = fn(tx); err != nil {
+		if rbErr := rollback(); rbErr != nil {
+			return fmt.Errorf("%w (rollback: %v)", err, rbErr)
+		}
+		return err
+	}
+	return commit()
+}
+
+func withSavepoint(ctx context.Context, tx *sql.Tx, dialect observable.SqlType, fn func(tx *sql.Tx) error) error {
+	name := fmt.Sprintf("sp_%d", atomic.AddUint64(&savepointCounter, 1))
+	save, release, rollback := savepointStatements(dialect, name)
+	if _, err := tx.ExecContext(ctx, save); err != nil {
+		return err
+	}
+	return finishTx(fn, tx, func() error {
+		if release == "" {
+			return nil
+		}
+		_, err := tx.ExecContext(ctx, release)
+		return err
+	}, func() error {
+		_, err := tx.ExecContext(ctx, rollback)
+		return err
+	})
+}
+
+// savepointStatements returns the statements creating, releasing and
+// rolling back to a savepoint. SQL Server and Oracle have no release, the
+// savepoint simply ends with the transaction.
+func savepointStatements(dialect observable.SqlType, name string) (string, string, string) {
+	switch dialect {
+	case observable.MSSQL:
+		return "SAVE TRANSACTION " + name, "", "ROLLBACK TRANSACTION " + name
+	case observable.ORACLE:
+		return "SAVEPOINT " + name, "", "ROLLBACK TO SAVEPOINT " + name
+	}
+	return "SAVEPOINT " + name, "RELEASE SAVEPOINT " + name, "ROLLBACK TO SAVEPOINT " + name
+}
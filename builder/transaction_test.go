@@ -0,0 +1,189 @@
+package builder_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	builder "github.com/zhuan69/go-simple-sql-builder/builder"
+	observable "github.com/zhuan69/go-simple-sql-builder/observable"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestItCanRunBuilderInsideTransaction(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer db.Close()
+
+	t.Run("Testing it commit when the callback succeed", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT id FROM users WHERE id=?").WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		mock.ExpectCommit()
+		err := builder.WithTx(context.Background(), db, func(tx *sql.Tx) error {
+			sb := builder.NewMysqlBuilder(context.Background(), tx, "users")
+			sb.Select([]string{"id"}).Where("id", 1)
+			var id int
+			return sb.RowQuery().Scan(&id)
+		})
+		assert.NoError(t, err)
+	})
+	t.Run("Testing it rollback when the callback failed", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+		err := builder.WithTx(context.Background(), db, func(tx *sql.Tx) error {
+			return errors.New("failed")
+		})
+		assert.EqualError(t, err, "failed")
+	})
+	t.Run("Testing it use savepoint on nested transaction", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec("SAVEPOINT sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("ROLLBACK TO SAVEPOINT sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("SAVEPOINT sp_2").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("RELEASE SAVEPOINT sp_2").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+		err := builder.WithTx(context.Background(), db, func(tx *sql.Tx) error {
+			nestedErr := builder.WithTx(context.Background(), tx, func(tx *sql.Tx) error {
+				return errors.New("nested failed")
+			})
+			assert.EqualError(t, nestedErr, "nested failed")
+			return builder.WithTx(context.Background(), tx, func(tx *sql.Tx) error {
+				return nil
+			})
+		})
+		assert.NoError(t, err)
+	})
+	t.Run("Testing it retry on serialization failure", func(t *testing.T) {
+		serializationErr := &pgconn.PgError{Code: "40001"}
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+		attempt := 0
+		err := builder.WithTx(context.Background(), db, func(tx *sql.Tx) error {
+			attempt++
+			if attempt == 1 {
+				return serializationErr
+			}
+			return nil
+		}, builder.TxOptions{MaxRetries: 2})
+		assert.NoError(t, err)
+		assert.Equal(t, 2, attempt)
+	})
+	t.Run("Testing it stop retrying once the context is done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+		attempt := 0
+		err := builder.WithTx(ctx, db, func(tx *sql.Tx) error {
+			attempt++
+			cancel()
+			return &pgconn.PgError{Code: "40001"}
+		}, builder.TxOptions{MaxRetries: 3})
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, 1, attempt)
+	})
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestItCanUseDialectSavepoints(t *testing.T) {
+	for _, v := range []struct {
+		dialect  observable.SqlType
+		save     string
+		rollback string
+	}{
+		{observable.MSSQL, `SAVE TRANSACTION sp_\d+`, `ROLLBACK TRANSACTION sp_\d+`},
+		{observable.ORACLE, `SAVEPOINT sp_\d+`, `ROLLBACK TO SAVEPOINT sp_\d+`},
+	} {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		opts := builder.TxOptions{Dialect: v.dialect}
+		mock.ExpectBegin()
+		mock.ExpectExec(v.save).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(v.rollback).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(v.save).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+		err = builder.WithTx(context.Background(), db, func(tx *sql.Tx) error {
+			nestedErr := builder.WithTx(context.Background(), tx, func(tx *sql.Tx) error {
+				return errors.New("nested failed")
+			}, opts)
+			assert.EqualError(t, nestedErr, "nested failed")
+			return builder.WithTx(context.Background(), tx, func(tx *sql.Tx) error {
+				return nil
+			}, opts)
+		}, opts)
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet(), v.dialect)
+		db.Close()
+	}
+}
+
+type fakePgxTx struct {
+	pgx.Tx
+	committed  int
+	rolledBack int
+}
+
+func (tx *fakePgxTx) Begin(ctx context.Context) (pgx.Tx, error) {
+	return tx, nil
+}
+
+func (tx *fakePgxTx) Commit(ctx context.Context) error {
+	tx.committed++
+	return nil
+}
+
+func (tx *fakePgxTx) Rollback(ctx context.Context) error {
+	tx.rolledBack++
+	return nil
+}
+
+func TestItCanNotRetryNestedPgxTransaction(t *testing.T) {
+	tx := &fakePgxTx{}
+	attempt := 0
+	err := builder.WithPgxTx(context.Background(), tx, func(tx pgx.Tx) error {
+		attempt++
+		if attempt < 3 {
+			return &pgconn.PgError{Code: "40P01"}
+		}
+		return nil
+	}, builder.TxOptions{MaxRetries: 5})
+	assert.Error(t, err, "nested transaction must not be retried")
+	assert.Equal(t, 1, attempt)
+	assert.Equal(t, 1, tx.rolledBack)
+	assert.Equal(t, 0, tx.committed)
+	assert.True(t, builder.IsSerializationFailure(err))
+}
+
+type fakePgxBeginner struct {
+	txs []*fakePgxTx
+}
+
+func (b *fakePgxBeginner) Begin(ctx context.Context) (pgx.Tx, error) {
+	tx := &fakePgxTx{}
+	b.txs = append(b.txs, tx)
+	return tx, nil
+}
+
+func TestItCanRetryPgxTransaction(t *testing.T) {
+	db := &fakePgxBeginner{}
+	attempt := 0
+	err := builder.WithPgxTx(context.Background(), db, func(tx pgx.Tx) error {
+		attempt++
+		if attempt < 3 {
+			return &pgconn.PgError{Code: "40P01"}
+		}
+		return nil
+	}, builder.TxOptions{MaxRetries: 5})
+	assert.NoError(t, err)
+	require.Len(t, db.txs, 3)
+	assert.Equal(t, 1, db.txs[0].rolledBack)
+	assert.Equal(t, 1, db.txs[2].committed)
+}
@@ -3,14 +3,25 @@ package constants
 const (
 	AND_KEY        = "AND"
 	DELETE_KEY     = "DELETE"
+	ERROR_KEY      = "ERROR"
+	FETCH_KEY      = "FETCH"
+	FINAL_KEY      = "FINAL"
+	GROUP_BY_KEY   = "GROUP BY"
 	INSERT_KEY     = "INSERT"
 	JOIN_KEY       = "JOIN"
 	WHERE_LIKE_KEY = "LIKE"
 	LIMIT_KEY      = "LIMIT"
+	LIMIT_BY_KEY   = "LIMIT BY"
 	UPDATE_KEY     = "UPDATE"
+	UPSERT_KEY     = "UPSERT"
 	OR_KEY         = "OR"
 	OFFSET_KEY     = "OFFSET"
 	ORDER_BY_KEY   = "ORDER BY"
+	PREWHERE_KEY   = "PREWHERE"
+	RAW_KEY        = "RAW"
+	RETURNING_KEY  = "RETURNING"
+	SAMPLE_KEY     = "SAMPLE"
+	SEEK_KEY       = "SEEK"
 	SELECT_KEY     = "SELECT"
 	WHERE_KEY      = "WHERE"
 )
@@ -1,9 +1,11 @@
 module github.com/zhuan69/go-simple-sql-builder
 
-go 1.19
+go 1.23
 
 require (
+	github.com/DATA-DOG/go-sqlmock v1.5.0
 	github.com/jackc/pgx/v5 v5.3.1
+	github.com/pmezard/go-difflib v1.0.0
 	github.com/stretchr/testify v1.8.1
 )
 
@@ -11,12 +13,10 @@ require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/jackc/pgpassfile v1.0.0 // indirect
 	github.com/jackc/pgservicefile v0.0.0-20221227161230-091c0ba34f0a // indirect
-	github.com/jackc/puddle/v2 v2.2.0 // indirect
 	github.com/kr/text v0.2.0 // indirect
-	github.com/pmezard/go-difflib v1.0.0 // indirect
 	github.com/rogpeppe/go-internal v1.9.0 // indirect
+	github.com/stretchr/objx v0.5.0 // indirect
 	golang.org/x/crypto v0.6.0 // indirect
-	golang.org/x/sync v0.1.0 // indirect
 	golang.org/x/text v0.7.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
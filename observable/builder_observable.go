@@ -1,7 +1,9 @@
 package observable
 
 import (
+	"database/sql"
 	"fmt"
+	"strconv"
 	"strings"
 
 	constants "github.com/zhuan69/go-simple-sql-builder/constants"
@@ -10,21 +12,43 @@ import (
 type SqlType string
 
 const (
-	MYSQL SqlType = "mysql"
-	PGSQL SqlType = "pgsql"
+	MYSQL      SqlType = "mysql"
+	PGSQL      SqlType = "pgsql"
+	MSSQL      SqlType = "mssql"
+	ORACLE     SqlType = "oracle"
+	CLICKHOUSE SqlType = "clickhouse"
+)
+
+type ParamStyle string
+
+const (
+	NAMED_COLON ParamStyle = ":name"
+	NAMED_AT    ParamStyle = "@name"
+	// NAMED_SQLC renders sqlc.arg(name) for sqlc query files, no driver can
+	// run it.
+	NAMED_SQLC ParamStyle = "sqlc.arg"
 )
 
 type SqlObserve struct {
-	query          strings.Builder
-	command        string
-	column         []string
-	value          []any
-	parameterized  bool
-	counter        int
-	called         int
-	tableName      string
-	typeSql        SqlType
-	paramterPrefix string
+	buf           []byte
+	command       string
+	column        []string
+	value         []any
+	parameterized bool
+	counter       int
+	called        int
+	tableName     string
+	typeSql       SqlType
+	top           int
+	hasTop        bool
+	output        []string
+	conflict      []string
+	paramStyle    ParamStyle
+	paramName     []string
+	literal       bool
+	sort          []string
+	expanded      bool
+	rows          int
 }
 
 var conditionalCommandQuery = []string{
@@ -36,11 +60,19 @@ var conditionalCommandQuery = []string{
 	constants.WHERE_KEY,
 }
 
+var clickhouseCommandQuery = []string{
+	constants.FINAL_KEY,
+	constants.LIMIT_BY_KEY,
+	constants.PREWHERE_KEY,
+	constants.SAMPLE_KEY,
+}
+
 var baseCommandQuery = []string{
 	constants.DELETE_KEY,
 	constants.INSERT_KEY,
 	constants.SELECT_KEY,
 	constants.UPDATE_KEY,
+	constants.UPSERT_KEY,
 }
 
 func NewSqlObserve(
@@ -76,53 +108,156 @@ func (so *SqlObserve) SetColumn(col ...string) {
 	so.column = append(so.column, col...)
 }
 
+// SetRows makes an INSERT render rows value lists, reading its values row
+// after row for the columns.
+func (so *SqlObserve) SetRows(rows int) {
+	so.rows = rows
+}
+
+// SetTop makes a SELECT render TOP (limit), a zero limit included.
+func (so *SqlObserve) SetTop(limit int) {
+	so.top = limit
+	so.hasTop = true
+}
+
+func (so *SqlObserve) SetOutput(col ...string) {
+	so.output = append(so.output, col...)
+}
+
+func (so *SqlObserve) SetConflictColumn(col ...string) {
+	so.conflict = append(so.conflict, col...)
+}
+
+func (so *SqlObserve) SetParameterName(style ParamStyle, name ...string) {
+	so.paramStyle = style
+	so.paramName = append(so.paramName, name...)
+}
+
 func (so *SqlObserve) GetValues() []any {
 	return so.value
 }
 
+func (so *SqlObserve) GetColumns() []string {
+	return so.column
+}
+
+func (so *SqlObserve) GetCommand() string {
+	return so.command
+}
+
+func (so *SqlObserve) IsParameterized() bool {
+	return so.parameterized
+}
+
+// GetQuery renders the observe as a standalone string.
 func (so *SqlObserve) GetQuery() string {
+	return string(so.AppendQuery(nil))
+}
+
+// AppendQuery renders the observe onto buf and returns the extended buffer,
+// so a whole statement can be written into a single buffer.
+func (so *SqlObserve) AppendQuery(buf []byte) []byte {
+	so.buf = buf
+	so.buildQuery()
+	buf = so.buf
+	so.buf = nil
+	return buf
+}
+
+// SizeHint estimates how many bytes the observe renders to, letting the
+// caller grow its buffer once before rendering.
+func (so *SqlObserve) SizeHint() int {
+	size := len(so.command) + len(so.tableName) + 8
+	for _, v := range so.column {
+		size += len(v) + 8
+	}
+	return size + len(so.output)*16
+}
+
+func (so *SqlObserve) buildQuery() {
 	colSize := len(so.column)
 	valSize := len(so.value)
-	if so.command == constants.WHERE_KEY && so.called > 1 {
+	if so.command == constants.ERROR_KEY {
+		so.buf = fmt.Append(so.buf, so.value[0])
+		return
+	}
+	if so.command == constants.RAW_KEY {
+		so.writeString(so.column[0])
+		return
+	}
+	if so.isClickhouseCommandQuery() && so.typeSql != CLICKHOUSE {
+		so.buf = fmt.Appendf(so.buf, " %s IS NOT SUPPORTED BY %s", so.command, so.typeSql)
+		return
+	}
+	if (so.command == constants.WHERE_KEY || so.command == constants.PREWHERE_KEY) && so.called > 1 {
 		so.command = constants.AND_KEY
 	}
 	if so.isBaseCommandQuery() {
-		if so.command == constants.INSERT_KEY {
+		switch so.command {
+		case constants.DELETE_KEY:
+			so.buildDeleteQuery()
+		case constants.INSERT_KEY:
+			if so.rows > 1 {
+				colSize = colSize * so.rows
+			}
 			if colSize != valSize {
-				return fmt.Sprintf("EXPECTED COLUMN AGRS:%d BUT GOT:%d", colSize, valSize)
+				so.buildArgsError(colSize, valSize)
+				return
 			}
 			so.buildInsertQuery()
-			return so.query.String()
-		}
-		if so.command == constants.SELECT_KEY {
+		case constants.SELECT_KEY:
 			so.buildSelectQuery()
-			return so.query.String()
-		}
-		if so.command == constants.UPDATE_KEY {
+		case constants.UPDATE_KEY:
 			if colSize != valSize {
-				return fmt.Sprintf("EXPECTED COLUMN AGRS:%d BUT GOT:%d", colSize, valSize)
+				so.buildArgsError(colSize, valSize)
+				return
 			}
 			so.buildUpdateQuery()
-			return so.query.String()
+		case constants.UPSERT_KEY:
+			if colSize != valSize {
+				so.buildArgsError(colSize, valSize)
+				return
+			}
+			so.buildUpsertQuery()
 		}
+		return
+	}
+	switch so.command {
+	case constants.LIMIT_KEY, constants.OFFSET_KEY, constants.SAMPLE_KEY:
+		so.buildPagingQuery()
+		return
+	case constants.SEEK_KEY:
+		so.buildSeekQuery()
+		return
+	case constants.GROUP_BY_KEY, constants.RETURNING_KEY:
+		so.buildColumnListQuery()
+		return
+	case constants.LIMIT_BY_KEY:
+		so.buildLimitByQuery()
+		return
+	case constants.FINAL_KEY:
+		so.formatQuery(so.command)
+		return
+	case constants.FETCH_KEY:
+		so.buildFetchQuery()
+		return
 	}
 	if strings.Contains(so.command, constants.JOIN_KEY) {
 		so.buildJoinQuery()
-		return so.query.String()
+		return
 	}
-	if so.isParameterizedConditionalQuery() {
+	if so.isParameterizedConditionalQuery() || so.parameterized {
 		so.parameteredQuery(so.command, so.column[0], so.counter)
-		return so.query.String()
-	}
-	if so.parameterized {
-		so.parameteredQuery(so.command, so.column[0], so.counter)
-		return so.query.String()
+		return
 	}
 	if so.column[0] == "" && so.value == nil {
-		return so.query.String()
+		return
 	}
 	so.normalQuery(so.command, so.column[0], so.value[0])
-	return so.query.String()
+}
+
+func (so *SqlObserve) buildArgsError(colSize int, valSize int) {
+	so.buf = fmt.Appendf(so.buf, "EXPECTED COLUMN AGRS:%d BUT GOT:%d", colSize, valSize)
 }
 
 func (so *SqlObserve) buildJoinQuery() {
@@ -131,93 +266,277 @@ func (so *SqlObserve) buildJoinQuery() {
 	so.formatQuery(query)
 }
 
+func (so *SqlObserve) buildPagingQuery() {
+	buf := append(so.buf, ' ')
+	buf = append(buf, so.command...)
+	buf = append(buf, ' ')
+	so.buf = appendValue(buf, so.value[0])
+}
+
+func (so *SqlObserve) buildLimitByQuery() {
+	so.formatQuery(fmt.Sprintf("%s %v BY %s", constants.LIMIT_KEY, so.value[0], so.joinColumns(so.column)))
+	so.value = nil
+}
+
+func (so *SqlObserve) buildFetchQuery() {
+	if len(so.column) > 0 {
+		so.formatQuery(fmt.Sprintf("%s %s", constants.ORDER_BY_KEY, so.column[0]))
+	}
+	offset, limit := so.value[0].(int), so.value[1].(int)
+	if offset >= 0 {
+		so.formatQuery(fmt.Sprintf("%s %d ROWS", constants.OFFSET_KEY, offset))
+	}
+	if limit >= 0 {
+		next := "NEXT"
+		if offset < 0 {
+			next = "FIRST"
+		}
+		so.formatQuery(fmt.Sprintf("%s %s %d ROWS ONLY", so.command, next, limit))
+	}
+}
+
+func (so *SqlObserve) buildColumnListQuery() {
+	buf := append(so.buf, ' ')
+	buf = append(buf, so.command...)
+	buf = append(buf, ' ')
+	so.buf = so.appendColumns(buf, so.column)
+}
+
+func (so *SqlObserve) appendOutputClause(buf []byte) []byte {
+	if len(so.output) == 0 {
+		return buf
+	}
+	prefix := "INSERTED."
+	if so.command == constants.DELETE_KEY {
+		prefix = "DELETED."
+	}
+	buf = append(buf, " OUTPUT "...)
+	for i, v := range so.output {
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+		buf = append(buf, prefix...)
+		buf = so.appendIdentifier(buf, v)
+	}
+	return buf
+}
+
+func (so *SqlObserve) appendColumns(buf []byte, columns []string) []byte {
+	for i, v := range columns {
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+		buf = so.appendIdentifier(buf, v)
+	}
+	return buf
+}
+
+func (so *SqlObserve) joinColumns(columns []string) string {
+	return string(so.appendColumns(nil, columns))
+}
+
 func (so *SqlObserve) formatQuery(query string) {
-	so.query.WriteString(" " + query)
+	so.buf = append(so.buf, ' ')
+	so.writeString(query)
+}
+
+func (so *SqlObserve) writeString(query string) {
+	so.buf = append(so.buf, query...)
 }
 
 func (so *SqlObserve) buildSelectQuery() {
-	size := len(so.column) - 1
-	var col strings.Builder
-	for i, v := range so.column {
-		col.WriteString(v)
-		if i != size {
-			col.WriteString(",")
-		}
+	buf := append(so.buf, constants.SELECT_KEY...)
+	if so.hasTop {
+		buf = append(buf, " TOP ("...)
+		buf = strconv.AppendInt(buf, int64(so.top), 10)
+		buf = append(buf, ')')
 	}
-	so.query.WriteString(fmt.Sprintf("SELECT %s FROM %s", col.String(), so.tableName))
+	buf = append(buf, ' ')
+	buf = so.appendColumns(buf, so.column)
+	buf = append(buf, " FROM "...)
+	so.buf = so.appendIdentifier(buf, so.tableName)
 }
 
 func (so *SqlObserve) buildUpdateQuery() {
-	size := len(so.column) - 1
-	var q strings.Builder
+	buf := so.buf
+	if so.typeSql == CLICKHOUSE {
+		buf = append(buf, "ALTER TABLE "...)
+		buf = append(buf, so.tableName...)
+		buf = append(buf, " UPDATE "...)
+	} else {
+		buf = append(buf, "UPDATE "...)
+		buf = so.appendIdentifier(buf, so.tableName)
+		buf = append(buf, " SET "...)
+	}
 	for i, v := range so.column {
-		so.sanitizeParameterPrefix(i + 1)
-		argVal := so.value[i]
-		q.WriteString(fmt.Sprintf("%s=%s", v, so.paramterPrefix))
-		if i != size {
-			q.WriteString(",")
+		if i > 0 {
+			buf = append(buf, ',')
 		}
-		so.value[i] = argVal
+		buf = so.appendIdentifier(buf, v)
+		buf = append(buf, '=')
+		buf = so.appendParameter(buf, i+1, i)
 	}
-	so.query.WriteString(fmt.Sprintf("UPDATE %s SET %s", so.tableName, q.String()))
+	if so.typeSql != CLICKHOUSE {
+		buf = so.appendOutputClause(buf)
+	}
+	so.buf = buf
+}
+
+func (so *SqlObserve) buildDeleteQuery() {
+	if so.typeSql == CLICKHOUSE {
+		so.writeString("ALTER TABLE ")
+		so.writeString(so.tableName)
+		so.writeString(" DELETE")
+		return
+	}
+	buf := append(so.buf, "DELETE FROM "...)
+	buf = so.appendIdentifier(buf, so.tableName)
+	so.buf = so.appendOutputClause(buf)
 }
 
 func (so *SqlObserve) buildInsertQuery() {
-	size := len(so.column) - 1
-	col := strings.Builder{}
-	val := strings.Builder{}
-	col.WriteString("(")
-	val.WriteString("(")
-	for i, v := range so.column {
-		so.sanitizeParameterPrefix(i + 1)
-		argVal := so.value[i]
-		col.WriteString(v)
-		val.WriteString(so.paramterPrefix)
-		if i != size {
-			col.WriteString(",")
-			val.WriteString(",")
+	rows := max(so.rows, 1)
+	insertAll := rows > 1 && so.typeSql == ORACLE
+	buf := so.buf
+	if insertAll {
+		buf = append(buf, "INSERT ALL"...)
+	}
+	for r := 0; r < rows; r++ {
+		switch {
+		case insertAll:
+			buf = so.appendInsertInto(append(buf, " INTO "...))
+		case r == 0:
+			buf = so.appendInsertInto(append(buf, "INSERT INTO "...))
+			buf = so.appendOutputClause(buf)
+		default:
+			buf = append(buf, ',')
+		}
+		if r == 0 || insertAll {
+			buf = append(buf, " VALUES "...)
+		}
+		buf = append(buf, '(')
+		for i := range so.column {
+			index := r*len(so.column) + i
+			if i > 0 {
+				buf = append(buf, ',')
+			}
+			buf = so.appendParameter(buf, index+1, index)
 		}
-		so.value[i] = argVal
+		buf = append(buf, ')')
 	}
-	col.WriteString(")")
-	val.WriteString(")")
-	so.query.WriteString(fmt.Sprintf("INSERT INTO %s %s VALUES %s", so.tableName, col.String(), val.String()))
+	if insertAll {
+		buf = append(buf, " SELECT 1 FROM dual"...)
+	}
+	so.buf = buf
+}
+
+func (so *SqlObserve) appendInsertInto(buf []byte) []byte {
+	buf = so.appendIdentifier(buf, so.tableName)
+	buf = append(buf, " ("...)
+	buf = so.appendColumns(buf, so.column)
+	return append(buf, ')')
 }
 
 func (so *SqlObserve) parameteredQuery(command string, col string, num int) {
-	so.sanitizeParameterPrefix(num)
-	query := fmt.Sprintf("%s %s=%s", command, col, so.paramterPrefix)
+	buf := append(so.buf, ' ')
 	if command == constants.WHERE_LIKE_KEY {
-		query = fmt.Sprintf("WHERE %s LIKE %s", col, so.paramterPrefix)
 		if so.called > 1 {
-			query = fmt.Sprintf("AND %s LIKE %s", col, so.paramterPrefix)
+			buf = append(buf, constants.AND_KEY...)
+		} else {
+			buf = append(buf, constants.WHERE_KEY...)
 		}
+		buf = append(buf, ' ')
+		buf = so.appendIdentifier(buf, col)
+		buf = append(buf, " LIKE "...)
+	} else {
+		buf = append(buf, command...)
+		buf = append(buf, ' ')
+		buf = so.appendIdentifier(buf, col)
+		buf = append(buf, '=')
 	}
-	so.formatQuery(query)
+	so.buf = so.appendParameter(buf, num, 0)
 }
 
 func (so *SqlObserve) normalQuery(command string, col string, val any) {
-	q := fmt.Sprintf("%s %s=%v", command, col, val)
+	buf := so.buf
+	if command == constants.ORDER_BY_KEY && so.called > 1 {
+		buf = append(buf, ',')
+	} else {
+		buf = append(buf, ' ')
+		buf = append(buf, command...)
+		buf = append(buf, ' ')
+	}
+	buf = so.appendIdentifier(buf, col)
 	if command == constants.ORDER_BY_KEY {
-		q = fmt.Sprintf("%s %s %v", command, col, val)
+		buf = append(buf, ' ')
+	} else {
+		buf = append(buf, '=')
 	}
 	so.value = nil
-	so.formatQuery(q)
+	so.buf = appendValue(buf, val)
+}
+
+// appendValue writes val the way %v formats it, skipping fmt for the
+// strings and integers clauses are usually built from.
+func appendValue(buf []byte, val any) []byte {
+	switch v := val.(type) {
+	case string:
+		return append(buf, v...)
+	case int:
+		return strconv.AppendInt(buf, int64(v), 10)
+	case int64:
+		return strconv.AppendInt(buf, v, 10)
+	}
+	return fmt.Append(buf, val)
+}
+
+// appendParameter writes the placeholder of the value at index, numbered
+// num for the dialects that number their placeholders.
+func (so *SqlObserve) appendParameter(buf []byte, num int, index int) []byte {
+	if so.literal {
+		return append(buf, so.formatLiteral(so.value[index])...)
+	}
+	if so.paramStyle != "" && index < len(so.paramName) {
+		return append(buf, so.namedParameter(so.paramName[index])...)
+	}
+	switch so.typeSql {
+	case MYSQL, CLICKHOUSE:
+		return append(buf, '?')
+	case PGSQL:
+		return strconv.AppendInt(append(buf, '$'), int64(num), 10)
+	case MSSQL:
+		return strconv.AppendInt(append(buf, "@p"...), int64(num), 10)
+	case ORACLE:
+		if named, ok := so.value[index].(sql.NamedArg); ok {
+			return append(append(buf, ':'), named.Name...)
+		}
+		return strconv.AppendInt(append(buf, ':'), int64(num), 10)
+	}
+	return buf
+}
+
+func (so *SqlObserve) parameter(num int, index int) string {
+	return string(so.appendParameter(nil, num, index))
 }
 
-func (so *SqlObserve) sanitizeParameterPrefix(num int) {
-	if so.typeSql == MYSQL {
-		so.paramterPrefix = "?"
+func (so *SqlObserve) namedParameter(name string) string {
+	if so.paramStyle == NAMED_AT {
+		return "@" + name
 	}
-	if so.typeSql == PGSQL {
-		so.paramterPrefix = fmt.Sprintf("$%d", num)
+	if so.paramStyle == NAMED_SQLC {
+		return fmt.Sprintf("sqlc.arg(%s)", name)
 	}
+	return ":" + name
 }
 
 func (so *SqlObserve) isParameterizedConditionalQuery() bool {
 	return querySearch(conditionalCommandQuery, 0, len(conditionalCommandQuery)-1, so.command) != -1
 }
 
+func (so *SqlObserve) isClickhouseCommandQuery() bool {
+	return querySearch(clickhouseCommandQuery, 0, len(clickhouseCommandQuery)-1, so.command) != -1
+}
+
 func (so *SqlObserve) isBaseCommandQuery() bool {
 	return querySearch(baseCommandQuery, 0, len(baseCommandQuery)-1, so.command) != -1
 }
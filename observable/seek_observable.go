@@ -0,0 +1,71 @@
+package observable
+
+import (
+	"fmt"
+	"strings"
+
+	constants "github.com/zhuan69/go-simple-sql-builder/constants"
+)
+
+// SetSeek sets the sort direction of every keyset column and whether the
+// comparison is expanded into OR conditions instead of a row comparison.
+// Expanded seeks carry one column and value per placeholder.
+func (so *SqlObserve) SetSeek(expanded bool, sort ...string) {
+	so.expanded = expanded
+	so.sort = append(so.sort, sort...)
+}
+
+// IsExpandedSeek reports whether the dialect can not compare the keyset as a
+// row value, or the sort directions are mixed.
+func IsExpandedSeek(typeSql SqlType, sort []string) bool {
+	if typeSql != PGSQL && typeSql != CLICKHOUSE {
+		return true
+	}
+	for _, v := range sort {
+		if !strings.EqualFold(v, sort[0]) {
+			return true
+		}
+	}
+	return false
+}
+
+func (so *SqlObserve) buildSeekQuery() {
+	command := constants.WHERE_KEY
+	if so.called > 1 {
+		command = constants.AND_KEY
+	}
+	if !so.expanded {
+		size := len(so.sort)
+		placeholders := make([]string, size)
+		for i := 0; i < size; i++ {
+			placeholders[i] = so.parameter(so.counter+i, i)
+		}
+		so.formatQuery(fmt.Sprintf("%s (%s) %s (%s)", command, so.joinColumns(so.column), seekOperator(so.sort[0]), strings.Join(placeholders, ",")))
+		return
+	}
+	index := 0
+	parts := make([]string, len(so.sort))
+	for i, sort := range so.sort {
+		conditions := make([]string, 0, i+1)
+		for j := 0; j <= i; j++ {
+			operator := "="
+			if j == i {
+				operator = seekOperator(sort)
+			}
+			conditions = append(conditions, fmt.Sprintf("%s%s%s", so.quoteIdentifier(so.column[index]), operator, so.parameter(so.counter+index, index)))
+			index++
+		}
+		parts[i] = strings.Join(conditions, " AND ")
+		if i > 0 {
+			parts[i] = "(" + parts[i] + ")"
+		}
+	}
+	so.formatQuery(fmt.Sprintf("%s (%s)", command, strings.Join(parts, " OR ")))
+}
+
+func seekOperator(sort string) string {
+	if strings.EqualFold(sort, "desc") {
+		return "<"
+	}
+	return ">"
+}
@@ -0,0 +1,78 @@
+package observable
+
+import "strings"
+
+// quoteIdentifier wraps plain (optionally dotted) identifiers with the
+// dialect quoting characters. Expressions such as "col as alias" or
+// "COUNT(*)" are left untouched so hand-written SQL keeps working.
+func (so *SqlObserve) quoteIdentifier(identifier string) string {
+	open, _ := so.identifierQuotes()
+	if open == "" || !isPlainIdentifier(identifier) {
+		return identifier
+	}
+	return string(so.appendIdentifier(nil, identifier))
+}
+
+// appendIdentifier writes the quoted identifier onto buf without building
+// the intermediate parts quoteIdentifier would.
+func (so *SqlObserve) appendIdentifier(buf []byte, identifier string) []byte {
+	open, close := so.identifierQuotes()
+	if open == "" || !isPlainIdentifier(identifier) {
+		return append(buf, identifier...)
+	}
+	for {
+		part, rest, more := strings.Cut(identifier, ".")
+		if part == "*" {
+			buf = append(buf, part...)
+		} else {
+			buf = append(buf, open...)
+			buf = append(buf, part...)
+			buf = append(buf, close...)
+		}
+		if !more {
+			return buf
+		}
+		buf = append(buf, '.')
+		identifier = rest
+	}
+}
+
+func (so *SqlObserve) identifierQuotes() (string, string) {
+	if so.typeSql == MSSQL {
+		return "[", "]"
+	}
+	if so.typeSql == ORACLE {
+		return `"`, `"`
+	}
+	return "", ""
+}
+
+func isPlainIdentifier(identifier string) bool {
+	for {
+		part, rest, more := strings.Cut(identifier, ".")
+		if !isPlainIdentifierPart(part) {
+			return false
+		}
+		if !more {
+			return true
+		}
+		identifier = rest
+	}
+}
+
+func isPlainIdentifierPart(part string) bool {
+	if part == "" {
+		return false
+	}
+	if part == "*" {
+		return true
+	}
+	for i, r := range part {
+		isLetter := r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+		isDigit := r >= '0' && r <= '9'
+		if !isLetter && !(isDigit && i > 0) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,117 @@
+package observable
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// SetLiteral makes the observe render its values inline instead of
+// placeholders. It is only meant for debugging output, never for execution.
+func (so *SqlObserve) SetLiteral() {
+	so.literal = true
+}
+
+func (so *SqlObserve) formatLiteral(val any) string {
+	if named, ok := val.(sql.NamedArg); ok {
+		val = named.Value
+	}
+	if valuer, ok := val.(driver.Valuer); ok {
+		if rv := reflect.ValueOf(val); rv.Kind() == reflect.Pointer && rv.IsNil() {
+			return "NULL"
+		}
+		v, err := valuer.Value()
+		if err != nil {
+			return so.quoteString(fmt.Sprintf("!ERROR: %s", err))
+		}
+		val = v
+	}
+	if val == nil {
+		return "NULL"
+	}
+	switch v := val.(type) {
+	case string:
+		return so.quoteString(v)
+	case []byte:
+		return so.formatBytes(v)
+	case time.Time:
+		return so.formatTime(v)
+	case bool:
+		return so.formatBool(v)
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return fmt.Sprint(v)
+	}
+	rv := reflect.ValueOf(val)
+	if rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return "NULL"
+		}
+		return so.formatLiteral(rv.Elem().Interface())
+	}
+	switch rv.Kind() {
+	case reflect.String:
+		return so.quoteString(rv.String())
+	case reflect.Bool:
+		return so.formatBool(rv.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return fmt.Sprint(rv.Interface())
+	}
+	return so.quoteString(fmt.Sprint(val))
+}
+
+func (so *SqlObserve) quoteString(val string) string {
+	if so.typeSql == MYSQL || so.typeSql == CLICKHOUSE {
+		val = strings.ReplaceAll(val, `\`, `\\`)
+	}
+	val = strings.ReplaceAll(val, "'", "''")
+	if so.typeSql == MSSQL {
+		return "N'" + val + "'"
+	}
+	return "'" + val + "'"
+}
+
+func (so *SqlObserve) formatBytes(val []byte) string {
+	encoded := strings.ToUpper(hex.EncodeToString(val))
+	switch so.typeSql {
+	case PGSQL:
+		return `'\x` + encoded + "'"
+	case MSSQL:
+		return "0x" + encoded
+	case ORACLE:
+		return "HEXTORAW('" + encoded + "')"
+	case CLICKHOUSE:
+		return "unhex('" + encoded + "')"
+	}
+	return "X'" + encoded + "'"
+}
+
+func (so *SqlObserve) formatTime(val time.Time) string {
+	switch so.typeSql {
+	case PGSQL:
+		return "'" + val.Format("2006-01-02 15:04:05.999999-07:00") + "'"
+	case ORACLE:
+		return "TIMESTAMP '" + val.Format("2006-01-02 15:04:05.999999") + "'"
+	case CLICKHOUSE:
+		return "'" + val.Format("2006-01-02 15:04:05") + "'"
+	}
+	return "'" + val.Format("2006-01-02 15:04:05.999999") + "'"
+}
+
+func (so *SqlObserve) formatBool(val bool) string {
+	if so.typeSql == MSSQL || so.typeSql == ORACLE {
+		if val {
+			return "1"
+		}
+		return "0"
+	}
+	if val {
+		return "TRUE"
+	}
+	return "FALSE"
+}
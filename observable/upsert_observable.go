@@ -0,0 +1,93 @@
+package observable
+
+import (
+	"fmt"
+	"strings"
+)
+
+func (so *SqlObserve) buildUpsertQuery() {
+	if so.typeSql == CLICKHOUSE {
+		so.buf = fmt.Appendf(so.buf, "%s IS NOT SUPPORTED BY %s", so.command, so.typeSql)
+		return
+	}
+	if so.typeSql == MSSQL || so.typeSql == ORACLE {
+		so.buildMergeQuery()
+		return
+	}
+	so.buildInsertQuery()
+	updates := so.upsertColumns()
+	if so.typeSql == MYSQL {
+		if len(updates) == 0 {
+			updates = so.conflict
+		}
+		set := make([]string, len(updates))
+		for i, v := range updates {
+			set[i] = fmt.Sprintf("%s=VALUES(%s)", v, v)
+		}
+		so.writeString(fmt.Sprintf(" ON DUPLICATE KEY UPDATE %s", strings.Join(set, ",")))
+		return
+	}
+	action := "DO NOTHING"
+	if len(updates) > 0 {
+		set := make([]string, len(updates))
+		for i, v := range updates {
+			set[i] = fmt.Sprintf("%s=EXCLUDED.%s", v, v)
+		}
+		action = fmt.Sprintf("DO UPDATE SET %s", strings.Join(set, ","))
+	}
+	so.writeString(fmt.Sprintf(" ON CONFLICT (%s) %s", so.joinColumns(so.conflict), action))
+}
+
+// buildMergeQuery renders the upsert as MERGE INTO, selecting the bound
+// values as the source row and matching it on the conflict columns.
+func (so *SqlObserve) buildMergeQuery() {
+	size := len(so.column)
+	source := make([]string, size)
+	insertCol := make([]string, size)
+	insertVal := make([]string, size)
+	for i, v := range so.column {
+		col := so.quoteIdentifier(v)
+		source[i] = fmt.Sprintf("%s AS %s", so.parameter(i+1, i), col)
+		insertCol[i] = col
+		insertVal[i] = "source." + col
+	}
+	on := make([]string, len(so.conflict))
+	for i, v := range so.conflict {
+		col := so.quoteIdentifier(v)
+		on[i] = fmt.Sprintf("target.%s=source.%s", col, col)
+	}
+	using := fmt.Sprintf("(SELECT %s) AS source", strings.Join(source, ","))
+	target := fmt.Sprintf("%s AS target", so.quoteIdentifier(so.tableName))
+	if so.typeSql == ORACLE {
+		using = fmt.Sprintf("(SELECT %s FROM dual) source", strings.Join(source, ","))
+		target = fmt.Sprintf("%s target", so.quoteIdentifier(so.tableName))
+	}
+	so.writeString(fmt.Sprintf("MERGE INTO %s USING %s ON (%s)", target, using, strings.Join(on, " AND ")))
+	if updates := so.upsertColumns(); len(updates) > 0 {
+		set := make([]string, len(updates))
+		for i, v := range updates {
+			set[i] = fmt.Sprintf("target.%s=source.%s", v, v)
+		}
+		so.writeString(fmt.Sprintf(" WHEN MATCHED THEN UPDATE SET %s", strings.Join(set, ",")))
+	}
+	so.writeString(fmt.Sprintf(" WHEN NOT MATCHED THEN INSERT (%s) VALUES (%s)", strings.Join(insertCol, ","), strings.Join(insertVal, ",")))
+	if so.typeSql == MSSQL {
+		so.buf = so.appendOutputClause(so.buf)
+	}
+}
+
+// upsertColumns returns the quoted columns that have to be updated when the
+// row already exists, which are all inserted columns except the conflict ones.
+func (so *SqlObserve) upsertColumns() []string {
+	conflict := make(map[string]bool, len(so.conflict))
+	for _, v := range so.conflict {
+		conflict[v] = true
+	}
+	var columns []string
+	for _, v := range so.column {
+		if !conflict[v] {
+			columns = append(columns, so.quoteIdentifier(v))
+		}
+	}
+	return columns
+}
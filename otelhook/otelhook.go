@@ -0,0 +1,97 @@
+// Package otelhook traces builder queries with OpenTelemetry spans.
+package otelhook
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	builder "github.com/zhuan69/go-simple-sql-builder/builder"
+	observable "github.com/zhuan69/go-simple-sql-builder/observable"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/zhuan69/go-simple-sql-builder/otelhook"
+
+// Options configures the hook returned by New.
+type Options struct {
+	// TracerProvider defaults to the global otel.GetTracerProvider().
+	TracerProvider trace.TracerProvider
+	// DisableStatement leaves db.statement out of the spans.
+	DisableStatement bool
+}
+
+type hook struct {
+	tracer    trace.Tracer
+	statement bool
+}
+
+type spanKey struct{}
+
+// New returns a builder.Hook starting a client span for every query, child
+// of the span in the builder context, with the db.system, db.statement,
+// db.sql.table and db.operation attributes.
+func New(opts Options) builder.Hook {
+	provider := opts.TracerProvider
+	if provider == nil {
+		provider = otel.GetTracerProvider()
+	}
+	return &hook{
+		tracer:    provider.Tracer(instrumentationName),
+		statement: !opts.DisableStatement,
+	}
+}
+
+func (h *hook) Before(ctx context.Context, query string, args []any) context.Context {
+	name := "query"
+	attrs := make([]attribute.KeyValue, 0, 4)
+	if info, ok := builder.QueryInfoFromContext(ctx); ok {
+		name = strings.TrimSpace(info.Operation + " " + info.Table)
+		attrs = append(attrs,
+			attribute.String("db.system", dbSystem(info.Dialect)),
+			attribute.String("db.sql.table", info.Table),
+			attribute.String("db.operation", info.Operation),
+		)
+	}
+	if h.statement {
+		attrs = append(attrs, attribute.String("db.statement", query))
+	}
+	ctx, span := h.tracer.Start(ctx, name, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+	return context.WithValue(ctx, spanKey{}, span)
+}
+
+func (h *hook) After(ctx context.Context, query string, args []any, duration time.Duration, err error) {
+	span, ok := ctx.Value(spanKey{}).(trace.Span)
+	if !ok {
+		return
+	}
+	if info, ok := builder.QueryInfoFromContext(ctx); ok && info.RowsAffected >= 0 {
+		span.SetAttributes(attribute.Int64("db.rows_affected", info.RowsAffected))
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// dbSystem maps the dialect to the db.system semantic convention value.
+func dbSystem(dialect observable.SqlType) string {
+	switch dialect {
+	case observable.PGSQL:
+		return "postgresql"
+	case observable.MYSQL:
+		return "mysql"
+	case observable.MSSQL:
+		return "mssql"
+	case observable.ORACLE:
+		return "oracle"
+	case observable.CLICKHOUSE:
+		return "clickhouse"
+	}
+	return "other_sql"
+}
@@ -0,0 +1,80 @@
+package otelhook_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	builder "github.com/zhuan69/go-simple-sql-builder/builder"
+	observable "github.com/zhuan69/go-simple-sql-builder/observable"
+	otelhook "github.com/zhuan69/go-simple-sql-builder/otelhook"
+	fakedb "github.com/zhuan69/go-simple-sql-builder/tests/mocks/fakedb"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func newProvider() (*sdktrace.TracerProvider, *tracetest.InMemoryExporter) {
+	exporter := tracetest.NewInMemoryExporter()
+	return sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)), exporter
+}
+
+func attrs(span tracetest.SpanStub) map[attribute.Key]attribute.Value {
+	res := map[attribute.Key]attribute.Value{}
+	for _, v := range span.Attributes {
+		res[v.Key] = v.Value
+	}
+	return res
+}
+
+func TestItCanTraceQueryAsChildSpan(t *testing.T) {
+	provider, exporter := newProvider()
+	ctx, parent := provider.Tracer("test").Start(context.Background(), "handler")
+
+	fake := fakedb.New(t, observable.PGSQL)
+	fake.ExpectQuery("SELECT id FROM users WHERE id=?").WithArgs(1).WillReturnRows([]string{"id"}, []any{int64(1)})
+	sb := builder.NewPgsqlBuilder(ctx, fake.Pgx(), "users")
+	_, err := builder.Get[int64](sb.Select([]string{"id"}).Where("id", 1).WithHooks(otelhook.New(otelhook.Options{TracerProvider: provider})))
+	require.NoError(t, err)
+	parent.End()
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+	span := spans[0]
+	assert.Equal(t, "SELECT users", span.Name)
+	assert.Equal(t, trace.SpanKindClient, span.SpanKind)
+	assert.Equal(t, parent.SpanContext().SpanID(), span.Parent.SpanID())
+	a := attrs(span)
+	assert.Equal(t, "postgresql", a["db.system"].AsString())
+	assert.Equal(t, "SELECT id FROM users WHERE id=$1", a["db.statement"].AsString())
+	assert.Equal(t, "users", a["db.sql.table"].AsString())
+	assert.Equal(t, "SELECT", a["db.operation"].AsString())
+	assert.Equal(t, codes.Unset, span.Status.Code)
+}
+
+func TestItCanRecordQueryErrorOnSpan(t *testing.T) {
+	provider, exporter := newProvider()
+	failure := errors.New("deadlock detected")
+	fake := fakedb.New(t, observable.MYSQL)
+	fake.ExpectQuery("UPDATE users SET name=? WHERE id=?").WillReturnError(failure)
+
+	sb := builder.NewMysqlBuilder(context.Background(), fake.DB(), "users")
+	_, err := sb.Update(map[string]any{"name": "zhuan"}).Where("id", 1).
+		WithHooks(otelhook.New(otelhook.Options{TracerProvider: provider, DisableStatement: true})).Exec()
+	require.Error(t, err)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	span := spans[0]
+	assert.Equal(t, "UPDATE users", span.Name)
+	assert.Equal(t, codes.Error, span.Status.Code)
+	assert.Equal(t, "mysql", attrs(span)["db.system"].AsString())
+	assert.NotContains(t, attrs(span), attribute.Key("db.statement"))
+	require.Len(t, span.Events, 1)
+	assert.Equal(t, "exception", span.Events[0].Name)
+}
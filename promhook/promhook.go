@@ -0,0 +1,114 @@
+// Package promhook collects Prometheus metrics of builder queries.
+package promhook
+
+import (
+	"context"
+	"time"
+
+	builder "github.com/zhuan69/go-simple-sql-builder/builder"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Options configures the Collector returned by NewCollector.
+type Options struct {
+	// Namespace prefixes the metric names, "sqlbuilder" by default.
+	Namespace string
+	// Buckets of the duration histogram, prometheus.DefBuckets by default.
+	Buckets []float64
+	// DisableFingerprint leaves the fingerprint label empty, it is the
+	// builder.FingerprintQuery hash otherwise.
+	DisableFingerprint bool
+}
+
+// Collector is a prometheus.Collector and a builder.Hook, counting the
+// queries, errors and rows of every builder it is registered on by table,
+// operation and query fingerprint.
+type Collector struct {
+	duration    *prometheus.HistogramVec
+	queries     *prometheus.CounterVec
+	errors      *prometheus.CounterVec
+	rows        *prometheus.HistogramVec
+	fingerprint bool
+}
+
+var _ prometheus.Collector = (*Collector)(nil)
+var _ builder.Hook = (*Collector)(nil)
+
+func NewCollector(opts Options) *Collector {
+	namespace := opts.Namespace
+	if namespace == "" {
+		namespace = "sqlbuilder"
+	}
+	buckets := opts.Buckets
+	if buckets == nil {
+		buckets = prometheus.DefBuckets
+	}
+	labels := []string{"table", "operation", "fingerprint"}
+	return &Collector{
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "query_duration_seconds",
+			Help:      "Duration of the builder queries.",
+			Buckets:   buckets,
+		}, labels),
+		queries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "queries_total",
+			Help:      "Number of builder queries executed.",
+		}, labels),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "query_errors_total",
+			Help:      "Number of builder queries that failed.",
+		}, labels),
+		rows: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "query_rows",
+			Help:      "Rows returned or affected by the builder queries.",
+			Buckets:   prometheus.ExponentialBuckets(1, 10, 6),
+		}, []string{"table", "operation"}),
+		fingerprint: !opts.DisableFingerprint,
+	}
+}
+
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	c.duration.Describe(ch)
+	c.queries.Describe(ch)
+	c.errors.Describe(ch)
+	c.rows.Describe(ch)
+}
+
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.duration.Collect(ch)
+	c.queries.Collect(ch)
+	c.errors.Collect(ch)
+	c.rows.Collect(ch)
+}
+
+func (c *Collector) Before(ctx context.Context, query string, args []any) context.Context {
+	return ctx
+}
+
+func (c *Collector) After(ctx context.Context, query string, args []any, duration time.Duration, err error) {
+	var table, operation, print string
+	rows := int64(-1)
+	if info, ok := builder.QueryInfoFromContext(ctx); ok {
+		table, operation = info.Table, info.Operation
+		rows = info.RowsAffected
+		if info.RowsReturned >= 0 {
+			rows = info.RowsReturned
+		}
+	}
+	if c.fingerprint {
+		print = builder.FingerprintQuery(query).Hash
+	}
+	c.duration.WithLabelValues(table, operation, print).Observe(duration.Seconds())
+	c.queries.WithLabelValues(table, operation, print).Inc()
+	if err != nil {
+		c.errors.WithLabelValues(table, operation, print).Inc()
+	}
+	if rows >= 0 && err == nil {
+		c.rows.WithLabelValues(table, operation).Observe(float64(rows))
+	}
+}
@@ -0,0 +1,52 @@
+package promhook
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	builder "github.com/zhuan69/go-simple-sql-builder/builder"
+	observable "github.com/zhuan69/go-simple-sql-builder/observable"
+	fakedb "github.com/zhuan69/go-simple-sql-builder/tests/mocks/fakedb"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type user struct {
+	ID int64 `db:"id"`
+}
+
+func TestItCanCollectQueryMetrics(t *testing.T) {
+	collector := NewCollector(Options{})
+	registry := prometheus.NewPedanticRegistry()
+	require.NoError(t, registry.Register(collector))
+
+	fake := fakedb.New(t, observable.MYSQL)
+	fake.ExpectQuery("SELECT id FROM users WHERE id=?").WillReturnRows([]string{"id"}, []any{int64(1)})
+	fake.ExpectQuery("SELECT id FROM users WHERE id=?").WillReturnRows([]string{"id"}, []any{int64(2)})
+	fake.ExpectQuery("DELETE FROM users WHERE id=?").WillReturnError(errors.New("lock wait timeout"))
+
+	for _, id := range []int{1, 2} {
+		sb := builder.NewMysqlBuilder(context.Background(), fake.DB(), "users").WithHooks(collector)
+		_, err := builder.Select[user](sb.Select([]string{"id"}).Where("id", id))
+		require.NoError(t, err)
+	}
+	_, err := builder.NewMysqlBuilder(context.Background(), fake.DB(), "users").WithHooks(collector).
+		Delete().Where("id", 1).Exec()
+	require.Error(t, err)
+
+	selectPrint := builder.FingerprintQuery("SELECT id FROM users WHERE id=?").Hash
+	assert.Equal(t, float64(2), testutil.ToFloat64(collector.queries.WithLabelValues("users", "SELECT", selectPrint)))
+	expected := `
+# HELP sqlbuilder_query_errors_total Number of builder queries that failed.
+# TYPE sqlbuilder_query_errors_total counter
+sqlbuilder_query_errors_total{fingerprint="` + builder.FingerprintQuery("DELETE FROM users WHERE id=?").Hash + `",operation="DELETE",table="users"} 1
+`
+	assert.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(expected), "sqlbuilder_query_errors_total"))
+	assert.Equal(t, 2, testutil.CollectAndCount(collector, "sqlbuilder_query_duration_seconds"))
+	assert.Equal(t, 1, testutil.CollectAndCount(collector, "sqlbuilder_query_rows"))
+}
@@ -0,0 +1,119 @@
+package fakedb
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+)
+
+type connector struct {
+	e *Executor
+}
+
+func (c connector) Connect(context.Context) (driver.Conn, error) {
+	return &conn{c.e}, nil
+}
+
+func (c connector) Driver() driver.Driver {
+	return fakeDriver{}
+}
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(string) (driver.Conn, error) {
+	return nil, errors.New("fakedb: use Executor.DB")
+}
+
+type conn struct {
+	e *Executor
+}
+
+func (c *conn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("fakedb: prepared statements are not supported")
+}
+
+func (c *conn) Close() error {
+	return nil
+}
+
+func (c *conn) Begin() (driver.Tx, error) {
+	return tx{}, nil
+}
+
+func (c *conn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	return tx{}, nil
+}
+
+// CheckNamedValue accepts every value as it is, so the recorded arguments
+// are the ones the builder sent.
+func (c *conn) CheckNamedValue(nv *driver.NamedValue) error {
+	return nil
+}
+
+func (c *conn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	exp, err := c.e.receive(query, namedValues(args))
+	if err != nil {
+		return nil, err
+	}
+	return &rows{columns: exp.columns, rows: exp.rows}, nil
+}
+
+func (c *conn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	exp, err := c.e.receive(query, namedValues(args))
+	if err != nil {
+		return nil, err
+	}
+	return driver.RowsAffected(exp.rowsAffected), nil
+}
+
+func namedValues(args []driver.NamedValue) []any {
+	values := make([]any, len(args))
+	for i, v := range args {
+		values[i] = v.Value
+		if v.Name != "" {
+			values[i] = sql.Named(v.Name, v.Value)
+		}
+	}
+	return values
+}
+
+type tx struct{}
+
+func (tx) Commit() error {
+	return nil
+}
+
+func (tx) Rollback() error {
+	return nil
+}
+
+type rows struct {
+	columns []string
+	rows    [][]any
+	current int
+}
+
+func (r *rows) Columns() []string {
+	return r.columns
+}
+
+func (r *rows) Close() error {
+	return nil
+}
+
+func (r *rows) Next(dest []driver.Value) error {
+	if r.current >= len(r.rows) {
+		return io.EOF
+	}
+	for i, v := range r.rows[r.current] {
+		value, err := driver.DefaultParameterConverter.ConvertValue(v)
+		if err != nil {
+			return err
+		}
+		dest[i] = value
+	}
+	r.current++
+	return nil
+}
@@ -0,0 +1,268 @@
+// Package fakedb provides a recording fake executor for the builders. It
+// records every query sent by RowQuery, RowsQuery and Exec, answers them
+// with canned rows, results or errors queued per expected query, and fails
+// the test with a diff when a query nobody expected arrives.
+package fakedb
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+
+	observable "github.com/zhuan69/go-simple-sql-builder/observable"
+	mocks "github.com/zhuan69/go-simple-sql-builder/tests/mocks/postgresql"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// TB is the part of testing.TB used by the fake executor.
+type TB interface {
+	Helper()
+	Errorf(format string, args ...any)
+	Cleanup(func())
+}
+
+// Call is a query received by the fake executor. SQL keeps the rendered
+// query, Normalized replaces the dialect placeholders with "?".
+type Call struct {
+	SQL        string
+	Normalized string
+	Args       []any
+}
+
+type anyArg struct{}
+
+// AnyArg matches any argument value in WithArgs.
+func AnyArg() any {
+	return anyArg{}
+}
+
+type Expectation struct {
+	pattern      *regexp.Regexp
+	query        string
+	args         []any
+	checkArgs    bool
+	columns      []string
+	rows         [][]any
+	rowsAffected int64
+	err          error
+	used         bool
+}
+
+// WithArgs makes the expectation only match queries sent with args.
+func (e *Expectation) WithArgs(args ...any) *Expectation {
+	e.args = args
+	e.checkArgs = true
+	return e
+}
+
+func (e *Expectation) WillReturnRows(columns []string, rows ...[]any) *Expectation {
+	e.columns = columns
+	e.rows = rows
+	return e
+}
+
+func (e *Expectation) WillReturnResult(rowsAffected int64) *Expectation {
+	e.rowsAffected = rowsAffected
+	return e
+}
+
+func (e *Expectation) WillReturnError(err error) *Expectation {
+	e.err = err
+	return e
+}
+
+type Executor struct {
+	t            TB
+	dialect      observable.SqlType
+	placeholder  *regexp.Regexp
+	mu           sync.Mutex
+	expectations []*Expectation
+	calls        []Call
+	db           *sql.DB
+}
+
+// New returns a fake executor for dialect, queries written in expectations
+// use "?" for every placeholder whatever the dialect renders. Unmet
+// expectations fail the test when it finishes.
+func New(t TB, dialect observable.SqlType) *Executor {
+	e := &Executor{t: t, dialect: dialect, placeholder: placeholderPattern(dialect)}
+	e.db = sql.OpenDB(connector{e})
+	t.Cleanup(func() {
+		t.Helper()
+		if err := e.ExpectationsWereMet(); err != nil {
+			t.Errorf("%s", err)
+		}
+		e.db.Close()
+	})
+	return e
+}
+
+func placeholderPattern(dialect observable.SqlType) *regexp.Regexp {
+	switch dialect {
+	case observable.PGSQL:
+		return regexp.MustCompile(`\$\d+`)
+	case observable.MSSQL:
+		return regexp.MustCompile(`@p\d+`)
+	case observable.ORACLE:
+		return regexp.MustCompile(`:\d+`)
+	}
+	return regexp.MustCompile(`\?`)
+}
+
+// ExpectQuery queues an expectation matching the whole normalized query.
+func (e *Executor) ExpectQuery(query string) *Expectation {
+	return e.expect(query, regexp.MustCompile("^"+regexp.QuoteMeta(strings.TrimSpace(query))+"$"))
+}
+
+// ExpectQueryRegexp queues an expectation matching the normalized query
+// against pattern.
+func (e *Executor) ExpectQueryRegexp(pattern string) *Expectation {
+	return e.expect(pattern, regexp.MustCompile(pattern))
+}
+
+func (e *Executor) expect(query string, pattern *regexp.Regexp) *Expectation {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	exp := &Expectation{query: query, pattern: pattern}
+	e.expectations = append(e.expectations, exp)
+	return exp
+}
+
+// Calls returns every query received so far, in order.
+func (e *Executor) Calls() []Call {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]Call{}, e.calls...)
+}
+
+func (e *Executor) ExpectationsWereMet() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	var pending []string
+	for _, v := range e.expectations {
+		if !v.used {
+			pending = append(pending, "  "+v.query)
+		}
+	}
+	if len(pending) > 0 {
+		return fmt.Errorf("fakedb: %d %s expectation(s) were not met:\n%s", len(pending), e.dialect, strings.Join(pending, "\n"))
+	}
+	return nil
+}
+
+// DB returns a *sql.DB sending its queries to the fake executor, for the
+// database/sql dialects.
+func (e *Executor) DB() *sql.DB {
+	return e.db
+}
+
+// Pgx returns a pgx querier sending its queries to the fake executor, for
+// the pgsql builder.
+func (e *Executor) Pgx() mocks.PgxPoolMock {
+	return pgxExecutor{e}
+}
+
+func (e *Executor) receive(query string, args []any) (*Expectation, error) {
+	e.t.Helper()
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	call := Call{SQL: query, Normalized: e.placeholder.ReplaceAllString(query, "?"), Args: args}
+	e.calls = append(e.calls, call)
+	for _, v := range e.expectations {
+		if v.used || !v.pattern.MatchString(call.Normalized) {
+			continue
+		}
+		if v.checkArgs && !argsEqual(v.args, args) {
+			continue
+		}
+		v.used = true
+		return v, v.err
+	}
+	err := fmt.Errorf("fakedb: unexpected %s query", e.dialect)
+	e.t.Errorf("%s", e.unexpectedMessage(call))
+	return nil, err
+}
+
+func (e *Executor) unexpectedMessage(call Call) string {
+	var msg strings.Builder
+	msg.WriteString(fmt.Sprintf("fakedb: unexpected %s query\n  query: %s\n  args:  %v\n", e.dialect, call.Normalized, call.Args))
+	for _, v := range e.expectations {
+		if v.used {
+			continue
+		}
+		diff, _ := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        difflib.SplitLines(v.query + "\n" + fmt.Sprint(v.args) + "\n"),
+			B:        difflib.SplitLines(call.Normalized + "\n" + fmt.Sprint(call.Args) + "\n"),
+			FromFile: "expected",
+			ToFile:   "actual",
+			Context:  1,
+		})
+		msg.WriteString("closest pending expectation:\n" + diff)
+		return msg.String()
+	}
+	msg.WriteString("no pending expectation left")
+	return msg.String()
+}
+
+func argsEqual(expected []any, actual []any) bool {
+	if len(expected) != len(actual) {
+		return false
+	}
+	for i, v := range expected {
+		if _, ok := v.(anyArg); ok {
+			continue
+		}
+		if !reflect.DeepEqual(normalizeArg(v), normalizeArg(actual[i])) {
+			return false
+		}
+	}
+	return true
+}
+
+// normalizeArg converts the value the way database/sql does before handing
+// it to the driver, so WithArgs(1) matches both int and int64.
+func normalizeArg(v any) any {
+	if named, ok := v.(sql.NamedArg); ok {
+		return sql.Named(named.Name, normalizeArg(named.Value))
+	}
+	if converted, err := driver.DefaultParameterConverter.ConvertValue(v); err == nil {
+		return converted
+	}
+	return v
+}
+
+type pgxExecutor struct {
+	e *Executor
+}
+
+func (p pgxExecutor) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	exp, err := p.e.receive(sql, args)
+	if err != nil {
+		return nil, err
+	}
+	return mocks.NewMockRows(exp.columns, exp.rows...), nil
+}
+
+func (p pgxExecutor) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	exp, err := p.e.receive(sql, args)
+	if err != nil {
+		return mocks.MockRow{Err: err}
+	}
+	return mocks.MockRow{Rows: mocks.NewMockRows(exp.columns, exp.rows...)}
+}
+
+func (p pgxExecutor) Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error) {
+	exp, err := p.e.receive(sql, arguments)
+	if err != nil {
+		return pgconn.CommandTag{}, err
+	}
+	return pgconn.NewCommandTag(fmt.Sprintf("EXEC %d", exp.rowsAffected)), nil
+}
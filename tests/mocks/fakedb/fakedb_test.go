@@ -0,0 +1,103 @@
+package fakedb_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+
+	builder "github.com/zhuan69/go-simple-sql-builder/builder"
+	observable "github.com/zhuan69/go-simple-sql-builder/observable"
+	fakedb "github.com/zhuan69/go-simple-sql-builder/tests/mocks/fakedb"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingT struct {
+	errors   []string
+	cleanups []func()
+}
+
+func (r *recordingT) Helper() {}
+
+func (r *recordingT) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func (r *recordingT) Cleanup(fn func()) {
+	r.cleanups = append(r.cleanups, fn)
+}
+
+func (r *recordingT) finish() {
+	for _, fn := range r.cleanups {
+		fn()
+	}
+}
+
+type user struct {
+	ID   int64  `db:"id"`
+	Name string `db:"name"`
+}
+
+func TestItCanAnswerDatabaseSqlBuilders(t *testing.T) {
+	ctx := context.Background()
+	for _, dialect := range []observable.SqlType{observable.MYSQL, observable.MSSQL, observable.ORACLE, observable.CLICKHOUSE} {
+		t.Run(string(dialect), func(t *testing.T) {
+			fake := fakedb.New(t, dialect)
+			fake.ExpectQueryRegexp(`FROM .?users.? WHERE .?id.?=\?`).WithArgs(1).
+				WillReturnRows([]string{"id", "name"}, []any{1, "zhuan"})
+			var sb builder.SqlBuilder[*sql.Row, *sql.Rows]
+			switch dialect {
+			case observable.MYSQL:
+				sb = builder.NewMysqlBuilder(ctx, fake.DB(), "users")
+			case observable.MSSQL:
+				sb = builder.NewMssqlBuilder(ctx, fake.DB(), "users")
+			case observable.ORACLE:
+				sb = builder.NewOracleBuilder(ctx, fake.DB(), "users")
+			case observable.CLICKHOUSE:
+				sb = builder.NewClickhouseBuilder(ctx, fake.DB(), "users")
+			}
+			u, err := builder.Get[user](sb.Select([]string{"id", "name"}).Where("id", 1))
+			require.NoError(t, err)
+			assert.Equal(t, user{ID: 1, Name: "zhuan"}, u)
+			require.Len(t, fake.Calls(), 1)
+			assert.Equal(t, []any{1}, fake.Calls()[0].Args)
+		})
+	}
+}
+
+func TestItCanAnswerPgsqlBuilders(t *testing.T) {
+	ctx := context.Background()
+	fake := fakedb.New(t, observable.PGSQL)
+	fake.ExpectQuery("SELECT id,name FROM users WHERE id=?").WithArgs(fakedb.AnyArg()).
+		WillReturnRows([]string{"id", "name"}, []any{int64(1), "zhuan"})
+	fake.ExpectQuery("DELETE FROM users WHERE id=?").WithArgs(1).WillReturnResult(1)
+	fake.ExpectQuery("UPDATE users SET name=? WHERE id=?").WillReturnError(errors.New("locked"))
+
+	u, err := builder.Get[user](builder.NewPgsqlBuilder(ctx, fake.Pgx(), "users").Select([]string{"id", "name"}).Where("id", 1))
+	require.NoError(t, err)
+	assert.Equal(t, "zhuan", u.Name)
+	affected, err := builder.NewPgsqlBuilder(ctx, fake.Pgx(), "users").Delete().Where("id", 1).Exec()
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), affected)
+	_, err = builder.NewPgsqlBuilder(ctx, fake.Pgx(), "users").Update(map[string]any{"name": "x"}).Where("id", 1).Exec()
+	assert.EqualError(t, err, "locked")
+	assert.Equal(t, "UPDATE users SET name=$1 WHERE id=$2", fake.Calls()[2].SQL)
+}
+
+func TestItCanReportUnexpectedQuery(t *testing.T) {
+	rt := &recordingT{}
+	fake := fakedb.New(rt, observable.PGSQL)
+	fake.ExpectQuery("SELECT id FROM users WHERE id=?").WithArgs(1)
+	_, err := builder.NewPgsqlBuilder(context.Background(), fake.Pgx(), "users").Select([]string{"id"}).Where("name", "zhuan").Exec()
+	assert.Error(t, err)
+	require.Len(t, rt.errors, 1)
+	assert.Contains(t, rt.errors[0], "unexpected pgsql query")
+	assert.Contains(t, rt.errors[0], "-SELECT id FROM users WHERE id=?")
+	assert.Contains(t, rt.errors[0], "+SELECT id FROM users WHERE name=?")
+	rt.finish()
+	require.Len(t, rt.errors, 2)
+	assert.Contains(t, rt.errors[1], "1 pgsql expectation(s) were not met")
+}
@@ -1 +1,274 @@
 package mocks
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockPgxPool is a testify mock implementing PgxPoolMock, it can be passed
+// to builder.NewPgsqlBuilder in place of a *pgxpool.Pool.
+type MockPgxPool struct {
+	mock.Mock
+}
+
+var _ PgxPoolMock = (*MockPgxPool)(nil)
+
+func (m *MockPgxPool) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	ret := m.Called(append([]any{ctx, sql}, args...)...)
+	rows, _ := ret.Get(0).(pgx.Rows)
+	return rows, ret.Error(1)
+}
+
+func (m *MockPgxPool) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	ret := m.Called(append([]any{ctx, sql}, args...)...)
+	return ret.Get(0).(pgx.Row)
+}
+
+func (m *MockPgxPool) Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error) {
+	ret := m.Called(append([]any{ctx, sql}, arguments...)...)
+	tag, _ := ret.Get(0).(pgconn.CommandTag)
+	return tag, ret.Error(1)
+}
+
+func (m *MockPgxPool) SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults {
+	ret := m.Called(ctx, b)
+	return ret.Get(0).(pgx.BatchResults)
+}
+
+// CopyFrom reads rowSrc and records its rows as a [][]any argument, so
+// expectations can match the copied rows.
+func (m *MockPgxPool) CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error) {
+	var rows [][]any
+	for rowSrc.Next() {
+		values, err := rowSrc.Values()
+		if err != nil {
+			return 0, err
+		}
+		rows = append(rows, values)
+	}
+	if err := rowSrc.Err(); err != nil {
+		return 0, err
+	}
+	ret := m.Called(ctx, tableName, columnNames, rows)
+	n, _ := ret.Get(0).(int64)
+	return n, ret.Error(1)
+}
+
+// MockRows is an in-memory pgx.Rows, returned by MockPgxPool.Query.
+type MockRows struct {
+	columns []string
+	rows    [][]any
+	current int
+	err     error
+	closed  bool
+	tag     string
+}
+
+var _ pgx.Rows = (*MockRows)(nil)
+
+func NewMockRows(columns []string, rows ...[]any) *MockRows {
+	return &MockRows{columns: columns, rows: rows}
+}
+
+// WithError makes the rows fail with err once every row has been read.
+func (r *MockRows) WithError(err error) *MockRows {
+	r.err = err
+	return r
+}
+
+// WithCommandTag sets the command tag reported once the rows are read, such
+// as "UPDATE 2", it defaults to "SELECT n".
+func (r *MockRows) WithCommandTag(tag string) *MockRows {
+	r.tag = tag
+	return r
+}
+
+func (r *MockRows) Close() {
+	r.closed = true
+}
+
+func (r *MockRows) IsClosed() bool {
+	return r.closed
+}
+
+func (r *MockRows) Err() error {
+	if r.current > len(r.rows) {
+		return r.err
+	}
+	return nil
+}
+
+func (r *MockRows) CommandTag() pgconn.CommandTag {
+	if r.tag != "" {
+		return pgconn.NewCommandTag(r.tag)
+	}
+	return pgconn.NewCommandTag(fmt.Sprintf("SELECT %d", len(r.rows)))
+}
+
+func (r *MockRows) FieldDescriptions() []pgconn.FieldDescription {
+	fields := make([]pgconn.FieldDescription, len(r.columns))
+	for i, v := range r.columns {
+		fields[i] = pgconn.FieldDescription{Name: v}
+	}
+	return fields
+}
+
+func (r *MockRows) Next() bool {
+	if r.closed {
+		return false
+	}
+	r.current++
+	if r.current > len(r.rows) {
+		r.closed = true
+		return false
+	}
+	return true
+}
+
+func (r *MockRows) Scan(dest ...any) error {
+	row := r.rows[r.current-1]
+	if len(dest) != len(row) {
+		return fmt.Errorf("mocks: expected %d destination arguments in Scan, not %d", len(row), len(dest))
+	}
+	for i, v := range row {
+		if err := assignValue(dest[i], v); err != nil {
+			return fmt.Errorf("mocks: can't scan column %q: %w", r.columns[i], err)
+		}
+	}
+	return nil
+}
+
+func (r *MockRows) Values() ([]any, error) {
+	return append([]any{}, r.rows[r.current-1]...), nil
+}
+
+func (r *MockRows) RawValues() [][]byte {
+	return nil
+}
+
+func (r *MockRows) Conn() *pgx.Conn {
+	return nil
+}
+
+// MockRow is a pgx.Row returned by MockPgxPool.QueryRow, reading the first
+// row of MockRows or returning pgx.ErrNoRows.
+type MockRow struct {
+	Rows *MockRows
+	Err  error
+}
+
+func (r MockRow) Scan(dest ...any) error {
+	if r.Err != nil {
+		return r.Err
+	}
+	defer r.Rows.Close()
+	if !r.Rows.Next() {
+		if err := r.Rows.Err(); err != nil {
+			return err
+		}
+		return pgx.ErrNoRows
+	}
+	return r.Rows.Scan(dest...)
+}
+
+// MockBatchResults is a pgx.BatchResults returned by MockPgxPool.SendBatch,
+// handing out its results in order. A nil *MockRows with an error fails
+// the query at that position.
+type MockBatchResults struct {
+	Results []MockBatchResult
+	current int
+	closed  bool
+}
+
+type MockBatchResult struct {
+	Rows *MockRows
+	Err  error
+}
+
+var _ pgx.BatchResults = (*MockBatchResults)(nil)
+
+func (b *MockBatchResults) next() (*MockRows, error) {
+	if b.closed {
+		return nil, fmt.Errorf("mocks: batch already closed")
+	}
+	if b.current >= len(b.Results) {
+		return nil, fmt.Errorf("mocks: no result left in batch")
+	}
+	res := b.Results[b.current]
+	b.current++
+	if res.Err != nil {
+		return nil, res.Err
+	}
+	return res.Rows, nil
+}
+
+func (b *MockBatchResults) Exec() (pgconn.CommandTag, error) {
+	rows, err := b.next()
+	if err != nil {
+		return pgconn.CommandTag{}, err
+	}
+	rows.Close()
+	return rows.CommandTag(), nil
+}
+
+func (b *MockBatchResults) Query() (pgx.Rows, error) {
+	rows, err := b.next()
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+func (b *MockBatchResults) QueryRow() pgx.Row {
+	rows, err := b.next()
+	return MockRow{Rows: rows, Err: err}
+}
+
+func (b *MockBatchResults) Close() error {
+	b.closed = true
+	return nil
+}
+
+func (b *MockBatchResults) IsClosed() bool {
+	return b.closed
+}
+
+func assignValue(dest any, src any) error {
+	if scanner, ok := dest.(sql.Scanner); ok {
+		return scanner.Scan(src)
+	}
+	dv := reflect.ValueOf(dest)
+	if dv.Kind() != reflect.Pointer || dv.IsNil() {
+		return fmt.Errorf("destination %T is not a pointer", dest)
+	}
+	dv = dv.Elem()
+	if src == nil {
+		dv.Set(reflect.Zero(dv.Type()))
+		return nil
+	}
+	if dv.Kind() == reflect.Pointer {
+		elem := reflect.New(dv.Type().Elem())
+		if err := assignValue(elem.Interface(), src); err != nil {
+			return err
+		}
+		dv.Set(elem)
+		return nil
+	}
+	sv := reflect.ValueOf(src)
+	if sv.Type().AssignableTo(dv.Type()) {
+		dv.Set(sv)
+		return nil
+	}
+	isNumberToString := dv.Kind() == reflect.String && sv.Kind() != reflect.String && sv.Kind() != reflect.Slice
+	if sv.Type().ConvertibleTo(dv.Type()) && !isNumberToString {
+		dv.Set(sv.Convert(dv.Type()))
+		return nil
+	}
+	return fmt.Errorf("unsupported conversion from %T to %s", src, dv.Type())
+}